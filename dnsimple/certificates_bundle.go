@@ -0,0 +1,311 @@
+package dnsimple
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// CertificateFiles represents the paths of the files of a certificate,
+// in the layout of the certbot live directory.
+type CertificateFiles struct {
+	// The server certificate.
+	Cert string
+
+	// The intermediate certificates.
+	Chain string
+
+	// The server certificate followed by the intermediate certificates.
+	FullChain string
+
+	// The private key.
+	PrivKey string
+}
+
+// Certificates parses the server certificate, followed by the intermediate certificates.
+func (b *CertificateBundle) Certificates() ([]*x509.Certificate, error) {
+	certificates, err := parseCertificatesPEM(b.ServerCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("server certificate: %v", err)
+	}
+	if len(certificates) != 1 {
+		return nil, fmt.Errorf("server certificate: expected 1 certificate, got %v", len(certificates))
+	}
+
+	for _, intermediate := range b.IntermediateCertificates {
+		chain, err := parseCertificatesPEM(intermediate)
+		if err != nil {
+			return nil, fmt.Errorf("intermediate certificate: %v", err)
+		}
+		certificates = append(certificates, chain...)
+	}
+	return certificates, nil
+}
+
+// Validate checks that the private key matches the server certificate,
+// and that each certificate of the chain is signed by the next one, up to the root
+// certificate when it's part of the bundle.
+func (b *CertificateBundle) Validate() error {
+	certificates, err := b.Certificates()
+	if err != nil {
+		return err
+	}
+
+	key, err := parsePrivateKeyPEM(b.PrivateKey)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(key.Public(), certificates[0].PublicKey) {
+		return fmt.Errorf("private key doesn't match the server certificate")
+	}
+
+	if b.RootCertificate != "" {
+		roots, err := parseCertificatesPEM(b.RootCertificate)
+		if err != nil {
+			return fmt.Errorf("root certificate: %v", err)
+		}
+		if last := certificates[len(certificates)-1]; !bytes.Equal(last.Raw, roots[0].Raw) {
+			certificates = append(certificates, roots[0])
+		}
+	}
+
+	for i := 0; i < len(certificates)-1; i++ {
+		if err := certificates[i].CheckSignatureFrom(certificates[i+1]); err != nil {
+			return fmt.Errorf("certificate %q is not signed by %q: %v", certificates[i].Subject.CommonName, certificates[i+1].Subject.CommonName, err)
+		}
+	}
+	return nil
+}
+
+// ChainPEM returns the PEM-encoded intermediate certificates.
+func (b *CertificateBundle) ChainPEM() []byte {
+	var chain bytes.Buffer
+	for _, intermediate := range b.IntermediateCertificates {
+		chain.WriteString(pemBlock(intermediate))
+	}
+	return chain.Bytes()
+}
+
+// FullChainPEM returns the PEM-encoded server certificate followed by
+// the intermediate certificates, as expected by most servers.
+// The root certificate is not included.
+func (b *CertificateBundle) FullChainPEM() []byte {
+	return append([]byte(pemBlock(b.ServerCertificate)), b.ChainPEM()...)
+}
+
+// TLSCertificate returns the bundle as a tls.Certificate, with the chain and the parsed leaf.
+// The bundle is validated first.
+func (b *CertificateBundle) TLSCertificate() (tls.Certificate, error) {
+	if err := b.Validate(); err != nil {
+		return tls.Certificate{}, err
+	}
+
+	certificate, err := tls.X509KeyPair(b.FullChainPEM(), []byte(b.PrivateKey))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	certificate.Leaf, err = x509.ParseCertificate(certificate.Certificate[0])
+	return certificate, err
+}
+
+// PKCS12 returns the bundle as a PKCS#12 archive protected by the password.
+// The bundle is validated first.
+//
+// The private key is encrypted with the PBE-SHA1-3DES algorithm, and the
+// archive is authenticated with a SHA-1 HMAC, which are supported by all
+// the platforms that import PKCS#12 archives.
+func (b *CertificateBundle) PKCS12(password string) ([]byte, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+
+	certificates, err := b.Certificates()
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKeyPEM(b.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodePKCS12(key, certificates, password)
+}
+
+// WriteCertbotLayout writes the bundle in the layout used by certbot:
+// the files are written in dir/archive/name, with a version number,
+// and dir/live/name contains symbolic links to the latest version.
+// The bundle is validated first.
+//
+// The files are written atomically, the private key is readable by its owner
+// only, and the archive directory is accessible by its owner only.
+// Existing versions are kept.
+func (b *CertificateBundle) WriteCertbotLayout(dir string, name string) (*CertificateFiles, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return nil, fmt.Errorf("invalid certificate name %q", name)
+	}
+
+	archive := filepath.Join(dir, "archive", name)
+	live := filepath.Join(dir, "live", name)
+	for _, d := range []string{archive, live} {
+		if err := os.MkdirAll(d, 0700); err != nil {
+			return nil, err
+		}
+	}
+
+	version, err := nextArchiveVersion(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data []byte
+		perm os.FileMode
+	}{
+		{"cert", []byte(pemBlock(b.ServerCertificate)), 0644},
+		{"chain", b.ChainPEM(), 0644},
+		{"fullchain", b.FullChainPEM(), 0644},
+		{"privkey", []byte(pemBlock(b.PrivateKey)), 0600},
+	}
+
+	paths := make([]string, len(files))
+	for i, file := range files {
+		archived := fmt.Sprintf("%v%d.pem", file.name, version)
+		if err := writeFileAtomic(filepath.Join(archive, archived), file.data, file.perm); err != nil {
+			return nil, err
+		}
+
+		paths[i] = filepath.Join(live, file.name+".pem")
+		target := filepath.Join("..", "..", "archive", name, archived)
+		if err := symlinkAtomic(target, paths[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return &CertificateFiles{Cert: paths[0], Chain: paths[1], FullChain: paths[2], PrivKey: paths[3]}, nil
+}
+
+// nextArchiveVersion returns the version that follows the latest version in the archive directory.
+func nextArchiveVersion(archive string) (int, error) {
+	entries, err := ioutil.ReadDir(archive)
+	if err != nil {
+		return 0, err
+	}
+
+	latest := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, "cert") || !strings.HasSuffix(name, ".pem") {
+			continue
+		}
+		if version, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "cert"), ".pem")); err == nil && version > latest {
+			latest = version
+		}
+	}
+	return latest + 1, nil
+}
+
+// writeFileAtomic writes the data to a temporary file in the same directory,
+// and renames it to the path, so that a crash never leaves a truncated file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// symlinkAtomic creates or replaces the symbolic link at path.
+func symlinkAtomic(target string, path string) error {
+	tmp := path + ".tmp"
+	os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// pemBlock returns the PEM data terminated by a newline.
+func pemBlock(data string) string {
+	data = strings.TrimSpace(data)
+	if data == "" {
+		return ""
+	}
+	return data + "\n"
+}
+
+func parseCertificatesPEM(data string) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("no PEM-encoded certificate found")
+	}
+	return certificates, nil
+}
+
+func parsePrivateKeyPEM(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM-encoded private key found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("invalid or unsupported private key %q", block.Type)
+}
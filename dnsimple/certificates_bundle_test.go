@@ -0,0 +1,267 @@
+package dnsimple
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/pkcs12"
+)
+
+// newTestCertificateBundle returns a bundle with a server certificate
+// signed by an intermediate certificate, signed by a root certificate.
+func newTestCertificateBundle(t *testing.T) *CertificateBundle {
+	issue := func(template *x509.Certificate, parent *x509.Certificate, parentKey crypto.Signer) (*x509.Certificate, *ecdsa.PrivateKey, string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("GenerateKey() returned error: %v", err)
+		}
+		if parent == nil {
+			parent, parentKey = template, key
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+		if err != nil {
+			t.Fatalf("CreateCertificate() returned error: %v", err)
+		}
+		certificate, _ := x509.ParseCertificate(der)
+		return certificate, key, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	}
+
+	template := func(serial int64, name string, ca bool) *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber:          big.NewInt(serial),
+			Subject:               pkix.Name{CommonName: name},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  ca,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+			DNSNames:              []string{name},
+		}
+	}
+
+	root, rootKey, rootPEM := issue(template(1, "Test Root", true), nil, nil)
+	intermediate, intermediateKey, intermediatePEM := issue(template(2, "Test Intermediate", true), root, rootKey)
+	_, serverKey, serverPEM := issue(template(3, "www.example.com", false), intermediate, intermediateKey)
+
+	keyDER, _ := x509.MarshalECPrivateKey(serverKey)
+	return &CertificateBundle{
+		PrivateKey:               string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+		ServerCertificate:        serverPEM,
+		RootCertificate:          rootPEM,
+		IntermediateCertificates: []string{intermediatePEM},
+	}
+}
+
+func TestCertificateBundle_Validate(t *testing.T) {
+	bundle := newTestCertificateBundle(t)
+	if err := bundle.Validate(); err != nil {
+		t.Fatalf("CertificateBundle.Validate() returned error: %v", err)
+	}
+
+	other := newTestCertificateBundle(t)
+
+	wrongKey := *bundle
+	wrongKey.PrivateKey = other.PrivateKey
+	if err := wrongKey.Validate(); err == nil || !strings.Contains(err.Error(), "private key") {
+		t.Errorf("CertificateBundle.Validate() expected to reject a mismatched private key, got `%v`", err)
+	}
+
+	wrongChain := *bundle
+	wrongChain.IntermediateCertificates = other.IntermediateCertificates
+	if err := wrongChain.Validate(); err == nil || !strings.Contains(err.Error(), "is not signed by") {
+		t.Errorf("CertificateBundle.Validate() expected to reject a mismatched chain, got `%v`", err)
+	}
+
+	wrongRoot := *bundle
+	wrongRoot.RootCertificate = other.RootCertificate
+	if err := wrongRoot.Validate(); err == nil {
+		t.Errorf("CertificateBundle.Validate() expected to reject a mismatched root")
+	}
+}
+
+func TestCertificateBundle_FullChainPEM(t *testing.T) {
+	bundle := newTestCertificateBundle(t)
+
+	fullChain := string(bundle.FullChainPEM())
+	if want, got := bundle.ServerCertificate+bundle.IntermediateCertificates[0], fullChain; want != got {
+		t.Errorf("CertificateBundle.FullChainPEM() expected to be `%v`, got `%v`", want, got)
+	}
+	if strings.Contains(fullChain, bundle.RootCertificate) {
+		t.Errorf("CertificateBundle.FullChainPEM() expected not to contain the root certificate")
+	}
+}
+
+func TestCertificateBundle_TLSCertificate(t *testing.T) {
+	bundle := newTestCertificateBundle(t)
+
+	certificate, err := bundle.TLSCertificate()
+	if err != nil {
+		t.Fatalf("CertificateBundle.TLSCertificate() returned error: %v", err)
+	}
+
+	if want, got := 2, len(certificate.Certificate); want != got {
+		t.Errorf("CertificateBundle.TLSCertificate() expected %v certificates, got %v", want, got)
+	}
+	if want, got := "www.example.com", certificate.Leaf.Subject.CommonName; want != got {
+		t.Errorf("CertificateBundle.TLSCertificate() returned Leaf expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+// readTestPKCS12Bundle returns the bundle of testdata/pkcs12, with an RSA private key.
+// testdata/pkcs12/openssl.p12 was exported from it by OpenSSL 3.0, with:
+//
+//	openssl pkcs12 -export -inkey server.key -in server.pem -certfile intermediate.pem \
+//		-keypbe PBE-SHA1-3DES -certpbe PBE-SHA1-3DES -macalg sha1 -iter 2048 -passout pass:sécret
+func readTestPKCS12Bundle(t *testing.T) *CertificateBundle {
+	read := func(name string) string {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", "pkcs12", name))
+		if err != nil {
+			t.Fatalf("Unable to read PKCS#12 fixture: %v", err)
+		}
+		return string(data)
+	}
+
+	return &CertificateBundle{
+		ServerCertificate:        read("server.pem"),
+		RootCertificate:          read("root.pem"),
+		IntermediateCertificates: []string{read("intermediate.pem")},
+		PrivateKey:               read("server.key"),
+	}
+}
+
+// decodePKCS12 decodes the archive with golang.org/x/crypto/pkcs12,
+// which verifies its MAC and decrypts its private key.
+func decodePKCS12(t *testing.T, archive []byte, password string) []*pem.Block {
+	blocks, err := pkcs12.ToPEM(archive, password)
+	if err != nil {
+		t.Fatalf("pkcs12.ToPEM() returned error: %v", err)
+	}
+	return blocks
+}
+
+func TestCertificateBundle_PKCS12(t *testing.T) {
+	for _, bundle := range []*CertificateBundle{newTestCertificateBundle(t), readTestPKCS12Bundle(t)} {
+		archive, err := bundle.PKCS12("sécret")
+		if err != nil {
+			t.Fatalf("CertificateBundle.PKCS12() returned error: %v", err)
+		}
+		blocks := decodePKCS12(t, archive, "sécret")
+
+		certificates, _ := bundle.Certificates()
+		if want, got := len(certificates)+1, len(blocks); want != got {
+			t.Fatalf("CertificateBundle.PKCS12() expected %v entries, got %v", want, got)
+		}
+		for i, certificate := range certificates {
+			if blocks[i].Type != "CERTIFICATE" || !bytes.Equal(certificate.Raw, blocks[i].Bytes) {
+				t.Errorf("CertificateBundle.PKCS12() entry %v expected to be the certificate %q", i, certificate.Subject.CommonName)
+			}
+		}
+
+		key, err := parsePrivateKeyPEM(string(pem.EncodeToMemory(blocks[len(blocks)-1])))
+		if err != nil {
+			t.Fatalf("CertificateBundle.PKCS12() returned an invalid private key: %v", err)
+		}
+		bundleKey, _ := parsePrivateKeyPEM(bundle.PrivateKey)
+		want, _ := marshalPKCS8PrivateKey(bundleKey)
+		if got, _ := marshalPKCS8PrivateKey(key); !bytes.Equal(want, got) {
+			t.Errorf("CertificateBundle.PKCS12() returned a private key that doesn't match the bundle")
+		}
+
+		if _, err := pkcs12.ToPEM(archive, "secret"); err == nil {
+			t.Errorf("CertificateBundle.PKCS12() returned an archive that can be decoded with the wrong password")
+		}
+	}
+
+	if _, err := newTestCertificateBundle(t).PKCS12(string(rune(0x1F512))); err == nil {
+		t.Errorf("CertificateBundle.PKCS12() expected to reject a password outside of the BMP")
+	}
+}
+
+func TestCertificateBundle_PKCS12_OpenSSL(t *testing.T) {
+	want, err := ioutil.ReadFile(filepath.Join("testdata", "pkcs12", "openssl.p12"))
+	if err != nil {
+		t.Fatalf("Unable to read PKCS#12 fixture: %v", err)
+	}
+
+	archive, err := readTestPKCS12Bundle(t).PKCS12("sécret")
+	if err != nil {
+		t.Fatalf("CertificateBundle.PKCS12() returned error: %v", err)
+	}
+
+	// The archive has the same entries and attributes as the one exported by OpenSSL.
+	if want, got := decodePKCS12(t, want, "sécret"), decodePKCS12(t, archive, "sécret"); !reflect.DeepEqual(want, got) {
+		t.Errorf("CertificateBundle.PKCS12() entries expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestPKCS12DeriveKey(t *testing.T) {
+	// The test vector of the PKCS#12 key derivation published with the Bouncy Castle test suite.
+	password, _ := bmpString("smeg")
+	salt := []byte{0x0A, 0x58, 0xCF, 0x64, 0x53, 0x0D, 0x82, 0x3F}
+
+	key := pkcs12DeriveKey(password, salt, 1, pkcs12KeyID, 24)
+	want := []byte{0x8A, 0xAA, 0xE6, 0x29, 0x7B, 0x6C, 0xB0, 0x46, 0x42, 0xAB, 0x5B, 0x07, 0x78, 0x51, 0x28, 0x4E, 0xB7, 0x12, 0x8F, 0x1A, 0x2A, 0x7F, 0xBC, 0xA3}
+	if !bytes.Equal(key, want) {
+		t.Errorf("pkcs12DeriveKey() returned %X, want %X", key, want)
+	}
+}
+
+func TestCertificateBundle_WriteCertbotLayout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dnsimple-certificates")
+	if err != nil {
+		t.Fatalf("TempDir() returned error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	bundle := newTestCertificateBundle(t)
+	if _, err := bundle.WriteCertbotLayout(dir, "www.example.com"); err != nil {
+		t.Fatalf("CertificateBundle.WriteCertbotLayout() returned error: %v", err)
+	}
+
+	renewed := newTestCertificateBundle(t)
+	files, err := renewed.WriteCertbotLayout(dir, "www.example.com")
+	if err != nil {
+		t.Fatalf("CertificateBundle.WriteCertbotLayout() returned error: %v", err)
+	}
+
+	if want, got := filepath.Join(dir, "live", "www.example.com", "fullchain.pem"), files.FullChain; want != got {
+		t.Errorf("CertificateBundle.WriteCertbotLayout() returned FullChain expected to be `%v`, got `%v`", want, got)
+	}
+
+	target, _ := os.Readlink(files.PrivKey)
+	if want, got := filepath.Join("..", "..", "archive", "www.example.com", "privkey2.pem"), target; want != got {
+		t.Errorf("CertificateBundle.WriteCertbotLayout() privkey.pem expected to link to `%v`, got `%v`", want, got)
+	}
+
+	data, _ := ioutil.ReadFile(files.FullChain)
+	if want, got := string(renewed.FullChainPEM()), string(data); want != got {
+		t.Errorf("CertificateBundle.WriteCertbotLayout() fullchain.pem expected to be `%v`, got `%v`", want, got)
+	}
+
+	info, _ := os.Stat(files.PrivKey)
+	if want, got := os.FileMode(0600), info.Mode().Perm(); want != got {
+		t.Errorf("CertificateBundle.WriteCertbotLayout() privkey.pem mode expected to be `%v`, got `%v`", want, got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "archive", "www.example.com", "cert1.pem")); err != nil {
+		t.Errorf("CertificateBundle.WriteCertbotLayout() expected to keep the previous version: %v", err)
+	}
+
+	if _, err := bundle.WriteCertbotLayout(dir, "../escape"); err == nil {
+		t.Errorf("CertificateBundle.WriteCertbotLayout() expected to reject an invalid name")
+	}
+}
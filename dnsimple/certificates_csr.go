@@ -0,0 +1,129 @@
+package dnsimple
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// CertificateKeyType identifies the algorithm and the size of a private key.
+type CertificateKeyType string
+
+const (
+	// CertificateKeyRSA2048 is a 2048-bit RSA key.
+	CertificateKeyRSA2048 = CertificateKeyType("rsa2048")
+
+	// CertificateKeyRSA4096 is a 4096-bit RSA key.
+	CertificateKeyRSA4096 = CertificateKeyType("rsa4096")
+
+	// CertificateKeyECDSAP256 is an ECDSA key on the P-256 curve.
+	CertificateKeyECDSAP256 = CertificateKeyType("ecdsa-p256")
+
+	// CertificateKeyECDSAP384 is an ECDSA key on the P-384 curve.
+	CertificateKeyECDSAP384 = CertificateKeyType("ecdsa-p384")
+)
+
+// CertificateSigningRequest represents a PEM-encoded certificate signing request,
+// along with its PEM-encoded private key.
+type CertificateSigningRequest struct {
+	CSR        string
+	PrivateKey string
+}
+
+// GenerateCertificateRequest generates a private key and a certificate signing request
+// for the common name and the alternate names.
+//
+// The common name is included in the alternate names of the request, as required
+// by the certificate authorities. The private key is PEM-encoded in the PKCS#1 format
+// for RSA keys, and in the SEC 1 format for ECDSA keys.
+func GenerateCertificateRequest(keyType CertificateKeyType, commonName string, alternateNames []string) (*CertificateSigningRequest, error) {
+	key, err := generatePrivateKey(keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{strings.ToLower(commonName)}
+	for _, name := range alternateNames {
+		name = strings.ToLower(name)
+		if !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: names[0]},
+		DNSNames: names,
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, err := encodePrivateKeyPEM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CertificateSigningRequest{
+		CSR:        string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})),
+		PrivateKey: keyPEM,
+	}, nil
+}
+
+// ParseCertificateRequest parses a PEM-encoded certificate signing request,
+// and checks its signature.
+func ParseCertificateRequest(data string) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, fmt.Errorf("no PEM-encoded certificate request found")
+	}
+
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := request.CheckSignature(); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func generatePrivateKey(keyType CertificateKeyType) (crypto.Signer, error) {
+	switch keyType {
+	case CertificateKeyRSA2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case CertificateKeyRSA4096:
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case CertificateKeyECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case CertificateKeyECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", keyType)
+	}
+}
+
+// encodePrivateKeyPEM returns the PEM encoding of an RSA or ECDSA private key,
+// which parsePrivateKeyPEM decodes.
+func encodePrivateKeyPEM(key crypto.Signer) (string, error) {
+	var block *pem.Block
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return "", err
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+	default:
+		return "", fmt.Errorf("unsupported private key type %T", key)
+	}
+	return string(pem.EncodeToMemory(block)), nil
+}
@@ -0,0 +1,342 @@
+package dnsimple
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCertificatePollInterval = 15 * time.Second
+	defaultCertificateRenewBefore  = 30
+)
+
+// CertificateEventType identifies a transition of a certificate managed by a CertificateManager.
+type CertificateEventType string
+
+const (
+	// CertificateEventPurchased is emitted when a new certificate is purchased.
+	CertificateEventPurchased = CertificateEventType("purchased")
+
+	// CertificateEventRenewalPurchased is emitted when the renewal of a certificate is purchased.
+	CertificateEventRenewalPurchased = CertificateEventType("renewal_purchased")
+
+	// CertificateEventIssueRequested is emitted when the issuance of a certificate is requested.
+	CertificateEventIssueRequested = CertificateEventType("issue_requested")
+
+	// CertificateEventStateChanged is emitted when the state of a certificate changes while it's being issued.
+	CertificateEventStateChanged = CertificateEventType("state_changed")
+
+	// CertificateEventIssued is emitted when a certificate is issued.
+	CertificateEventIssued = CertificateEventType("issued")
+
+	// CertificateEventDownloaded is emitted when a certificate and its private key are downloaded.
+	CertificateEventDownloaded = CertificateEventType("downloaded")
+
+	// CertificateEventFailed is emitted when a certificate can't be purchased, issued or downloaded.
+	CertificateEventFailed = CertificateEventType("failed")
+)
+
+// CertificateEvent represents a transition of a certificate managed by a CertificateManager.
+type CertificateEvent struct {
+	Type   CertificateEventType
+	Domain string
+
+	// The certificate, in its current state. For the purchase events,
+	// only the ID of the purchased certificate is known.
+	Certificate *Certificate
+
+	// The previous state of the certificate, for the state_changed events.
+	PreviousState string
+
+	// The reason of the failure, for the failed events.
+	Err error
+}
+
+// CertificateStateError represents a certificate that ended in a state other than issued.
+type CertificateStateError struct {
+	CertificateID int64
+	State         string
+}
+
+// Error implements the error interface.
+func (e *CertificateStateError) Error() string {
+	return fmt.Sprintf("certificate %v ended in state %v", e.CertificateID, e.State)
+}
+
+// ManagedCertificate represents an issued certificate, along with its PEM-encoded bundle.
+type ManagedCertificate struct {
+	Certificate *Certificate
+
+	// The server certificate, the chain and the private key.
+	Bundle *CertificateBundle
+}
+
+// CertificateManager purchases, issues, downloads and renews Let's Encrypt certificates.
+//
+// Issuance is asynchronous: the manager polls the certificate until it's issued,
+// and reports every transition to OnEvent.
+type CertificateManager struct {
+	client *Client
+
+	// OnEvent is called for every transition of a managed certificate.
+	OnEvent func(event CertificateEvent)
+
+	// The interval between two checks of the state of a certificate being issued.
+	// Defaults to 15 seconds.
+	PollInterval time.Duration
+
+	// The number of days before the expiration a certificate is renewed.
+	// Defaults to 30.
+	RenewBefore int
+
+	now func() time.Time
+}
+
+// NewCertificateManager returns a CertificateManager.
+func NewCertificateManager(c *Client) *CertificateManager {
+	return &CertificateManager{
+		client:       c,
+		PollInterval: defaultCertificatePollInterval,
+		RenewBefore:  defaultCertificateRenewBefore,
+		now:          time.Now,
+	}
+}
+
+// Obtain purchases a Let's Encrypt certificate for the domain, issues it,
+// waits for the issuance and downloads the bundle.
+//
+// When the certificate ends in the failed or cancelled state,
+// the returned error is a *CertificateStateError.
+func (m *CertificateManager) Obtain(ctx context.Context, accountID string, domainName string, attributes LetsencryptCertificateAttributes) (*ManagedCertificate, error) {
+	purchaseResponse, err := m.client.Certificates.PurchaseLetsencryptCertificate(accountID, domainName, attributes)
+	if err != nil {
+		return nil, m.fail(domainName, nil, fmt.Errorf("purchasing certificate: %v", err))
+	}
+	purchase := purchaseResponse.Data
+	certificateID := purchase.CertificateID
+	if certificateID == 0 {
+		certificateID = purchase.ID
+	}
+	m.emit(CertificateEvent{Type: CertificateEventPurchased, Domain: domainName, Certificate: &Certificate{ID: certificateID, State: purchase.State}})
+
+	certificateResponse, err := m.client.Certificates.IssueLetsencryptCertificate(accountID, domainName, certificateID)
+	if err != nil {
+		return nil, m.fail(domainName, &Certificate{ID: certificateID}, fmt.Errorf("issuing certificate: %v", err))
+	}
+	m.emit(CertificateEvent{Type: CertificateEventIssueRequested, Domain: domainName, Certificate: certificateResponse.Data})
+
+	return m.complete(ctx, accountID, domainName, certificateResponse.Data)
+}
+
+// Renew purchases the renewal of a Let's Encrypt certificate, issues it,
+// waits for the issuance of the new certificate and downloads its bundle.
+//
+// When the new certificate ends in the failed or cancelled state,
+// the returned error is a *CertificateStateError.
+func (m *CertificateManager) Renew(ctx context.Context, accountID string, domainName string, certificateID int64, attributes LetsencryptCertificateAttributes) (*ManagedCertificate, error) {
+	old := &Certificate{ID: certificateID}
+
+	renewalResponse, err := m.client.Certificates.PurchaseLetsencryptCertificateRenewal(accountID, domainName, certificateID, attributes)
+	if err != nil {
+		return nil, m.fail(domainName, old, fmt.Errorf("purchasing renewal: %v", err))
+	}
+	renewal := renewalResponse.Data
+	m.emit(CertificateEvent{Type: CertificateEventRenewalPurchased, Domain: domainName, Certificate: &Certificate{ID: renewal.NewCertificateID, State: renewal.State}})
+
+	certificateResponse, err := m.client.Certificates.IssueLetsencryptCertificateRenewal(accountID, domainName, certificateID, renewal.ID)
+	if err != nil {
+		return nil, m.fail(domainName, &Certificate{ID: renewal.NewCertificateID}, fmt.Errorf("issuing renewal: %v", err))
+	}
+	m.emit(CertificateEvent{Type: CertificateEventIssueRequested, Domain: domainName, Certificate: certificateResponse.Data})
+
+	return m.complete(ctx, accountID, domainName, certificateResponse.Data)
+}
+
+// RenewExpiring renews the issued Let's Encrypt certificates of the domain
+// that expire within RenewBefore days, unless they have already been renewed.
+// The certificates with auto-renewal enabled are left to DNSimple.
+//
+// Every certificate is processed even when one of the renewals fails,
+// and the returned error is the first error encountered.
+func (m *CertificateManager) RenewExpiring(ctx context.Context, accountID string, domainName string) ([]ManagedCertificate, error) {
+	certificates, err := m.listCertificates(accountID, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	renewBefore := m.RenewBefore
+	if renewBefore <= 0 {
+		renewBefore = defaultCertificateRenewBefore
+	}
+	deadline := m.currentTime().AddDate(0, 0, renewBefore)
+
+	var renewed []ManagedCertificate
+	var firstErr error
+	for _, certificate := range certificates {
+		if certificate.State != "issued" || certificate.AuthorityIdentifier != "letsencrypt" || certificate.AutoRenew {
+			continue
+		}
+
+		expiresOn, err := certificate.ExpiresOnTime()
+		if err != nil {
+			return renewed, fmt.Errorf("certificate %v: %v", certificate.ID, err)
+		}
+		if expiresOn.IsZero() || expiresOn.After(deadline) || hasSuccessor(certificate, expiresOn, certificates) {
+			continue
+		}
+
+		managed, err := m.Renew(ctx, accountID, domainName, certificate.ID, LetsencryptCertificateAttributes{})
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		renewed = append(renewed, *managed)
+	}
+
+	return renewed, firstErr
+}
+
+// Run renews the expiring certificates of the domains every interval, until the context is done.
+// Renewal errors are reported to OnEvent, and don't stop the manager.
+func (m *CertificateManager) Run(ctx context.Context, accountID string, domainNames []string, interval time.Duration) error {
+	for {
+		for _, domainName := range domainNames {
+			if _, err := m.RenewExpiring(ctx, accountID, domainName); err != nil && ctx.Err() == nil {
+				m.emit(CertificateEvent{Type: CertificateEventFailed, Domain: domainName, Err: err})
+			}
+		}
+
+		if err := sleep(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// currentTime returns the current time. It falls back to time.Now for a manager
+// that wasn't created with NewCertificateManager.
+func (m *CertificateManager) currentTime() time.Time {
+	if m.now == nil {
+		return time.Now()
+	}
+	return m.now()
+}
+
+// complete waits for the certificate to be issued, and downloads it.
+func (m *CertificateManager) complete(ctx context.Context, accountID string, domainName string, certificate *Certificate) (*ManagedCertificate, error) {
+	certificate, err := m.wait(ctx, accountID, domainName, certificate)
+	if err != nil {
+		return nil, m.fail(domainName, certificate, err)
+	}
+	m.emit(CertificateEvent{Type: CertificateEventIssued, Domain: domainName, Certificate: certificate})
+
+	bundle, err := m.download(accountID, domainName, certificate.ID)
+	if err != nil {
+		return nil, m.fail(domainName, certificate, err)
+	}
+	m.emit(CertificateEvent{Type: CertificateEventDownloaded, Domain: domainName, Certificate: certificate})
+
+	return &ManagedCertificate{Certificate: certificate, Bundle: bundle}, nil
+}
+
+// wait polls the certificate until it leaves the issuance states.
+func (m *CertificateManager) wait(ctx context.Context, accountID string, domainName string, certificate *Certificate) (*Certificate, error) {
+	interval := m.PollInterval
+	if interval <= 0 {
+		interval = defaultCertificatePollInterval
+	}
+
+	for {
+		switch certificate.State {
+		case "issued":
+			return certificate, nil
+		case "failed", "cancelled", "rejected", "refunded":
+			return certificate, &CertificateStateError{CertificateID: certificate.ID, State: certificate.State}
+		}
+
+		if err := sleep(ctx, interval); err != nil {
+			return certificate, err
+		}
+
+		certificateResponse, err := m.client.Certificates.GetCertificate(accountID, domainName, certificate.ID)
+		if err != nil {
+			return certificate, fmt.Errorf("getting certificate: %v", err)
+		}
+
+		previous := certificate.State
+		certificate = certificateResponse.Data
+		if certificate.State != previous {
+			m.emit(CertificateEvent{Type: CertificateEventStateChanged, Domain: domainName, Certificate: certificate, PreviousState: previous})
+		}
+	}
+}
+
+// download returns the bundle of the certificate, including the private key.
+func (m *CertificateManager) download(accountID string, domainName string, certificateID int64) (*CertificateBundle, error) {
+	bundleResponse, err := m.client.Certificates.DownloadCertificate(accountID, domainName, certificateID)
+	if err != nil {
+		return nil, fmt.Errorf("downloading certificate: %v", err)
+	}
+	bundle := bundleResponse.Data
+
+	keyResponse, err := m.client.Certificates.GetCertificatePrivateKey(accountID, domainName, certificateID)
+	if err != nil {
+		return nil, fmt.Errorf("getting private key: %v", err)
+	}
+	bundle.PrivateKey = keyResponse.Data.PrivateKey
+
+	return bundle, nil
+}
+
+func (m *CertificateManager) listCertificates(accountID string, domainName string) ([]Certificate, error) {
+	var certificates []Certificate
+	err := eachPage(func(options ListOptions) (*Pagination, error) {
+		certificatesResponse, err := m.client.Certificates.ListCertificates(accountID, domainName, &options)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificatesResponse.Data...)
+		return certificatesResponse.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing certificates for %v: %v", domainName, err)
+	}
+	return certificates, nil
+}
+
+func (m *CertificateManager) fail(domainName string, certificate *Certificate, err error) error {
+	m.emit(CertificateEvent{Type: CertificateEventFailed, Domain: domainName, Certificate: certificate, Err: err})
+	return err
+}
+
+func (m *CertificateManager) emit(event CertificateEvent) {
+	if m.OnEvent != nil {
+		m.OnEvent(event)
+	}
+}
+
+// hasSuccessor returns true if a more recent certificate for the same names
+// is issued or being issued, which means the certificate has already been renewed.
+func hasSuccessor(certificate Certificate, expiresOn time.Time, certificates []Certificate) bool {
+	for _, other := range certificates {
+		if other.ID == certificate.ID || !strings.EqualFold(other.CommonName, certificate.CommonName) {
+			continue
+		}
+
+		switch other.State {
+		case "failed", "cancelled", "rejected", "refunded":
+		case "issued":
+			if otherExpiresOn, err := other.ExpiresOnTime(); err == nil && otherExpiresOn.After(expiresOn) {
+				return true
+			}
+		default:
+			if other.ID > certificate.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,342 @@
+package dnsimple
+
+import (
+	"crypto"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"unicode/utf16"
+)
+
+const (
+	// pkcs12Iterations is the number of iterations of the key derivation, the default of OpenSSL.
+	pkcs12Iterations = 2048
+	pkcs12SaltLength = 8
+
+	// The purposes of the key derivation of RFC 7292 Appendix B.3.
+	pkcs12KeyID = 1
+	pkcs12IVID  = 2
+	pkcs12MACID = 3
+)
+
+var (
+	oidData                 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSHA1                 = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidPBEWithSHAAnd3KeyDES = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 3}
+	oidPKCS8ShroudedKeyBag  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidX509Certificate      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+	oidLocalKeyID           = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+
+	oidRSAEncryption  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidECPublicKey    = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidNamedCurveP224 = asn1.ObjectIdentifier{1, 3, 132, 0, 33}
+	oidNamedCurveP256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidNamedCurveP384 = asn1.ObjectIdentifier{1, 3, 132, 0, 34}
+	oidNamedCurveP521 = asn1.ObjectIdentifier{1, 3, 132, 0, 35}
+)
+
+// asn1NullValue is the ASN.1 NULL of the algorithm parameters. asn1.NullRawValue requires Go 1.9.
+var asn1NullValue = asn1.RawValue{Tag: 5}
+
+type pkcs12PFX struct {
+	Version  int
+	AuthSafe pkcs12ContentInfo
+	MacData  pkcs12MacData
+}
+
+type pkcs12ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue // [0] EXPLICIT, see pkcs12Explicit
+}
+
+type pkcs12MacData struct {
+	Mac        pkcs12DigestInfo
+	MacSalt    []byte
+	Iterations int
+}
+
+type pkcs12DigestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+type pkcs12SafeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue     // [0] EXPLICIT, see pkcs12Explicit
+	Attributes []pkcs12Attribute `asn1:"set"`
+}
+
+type pkcs12Attribute struct {
+	ID     asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+type pkcs12CertBag struct {
+	ID    asn1.ObjectIdentifier
+	Value []byte `asn1:"explicit,tag:0"`
+}
+
+type pkcs12PBEParams struct {
+	Salt       []byte
+	Iterations int
+}
+
+type pkcs8PrivateKeyInfo struct {
+	Version    int
+	Algorithm  pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+type pkcs12EncryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// encodePKCS12 encodes the private key and the certificates, the first
+// being the one of the key, as a PKCS#12 archive (RFC 7292).
+func encodePKCS12(key crypto.Signer, certificates []*x509.Certificate, password string) ([]byte, error) {
+	bmpPassword, err := bmpString(password)
+	if err != nil {
+		return nil, err
+	}
+
+	localKeyID := sha1.Sum(certificates[0].Raw)
+	localKeyIDValue, err := asn1.Marshal(localKeyID[:])
+	if err != nil {
+		return nil, err
+	}
+	keyAttributes := []pkcs12Attribute{{ID: oidLocalKeyID, Values: []asn1.RawValue{{FullBytes: localKeyIDValue}}}}
+
+	// The certificates, in a data content.
+	var certBags []pkcs12SafeBag
+	for i, certificate := range certificates {
+		certBag, err := asn1.Marshal(pkcs12CertBag{ID: oidX509Certificate, Value: certificate.Raw})
+		if err != nil {
+			return nil, err
+		}
+		bag := pkcs12SafeBag{ID: oidCertBag, Value: pkcs12Explicit(certBag)}
+		if i == 0 {
+			bag.Attributes = keyAttributes
+		}
+		certBags = append(certBags, bag)
+	}
+	certContent, err := pkcs12DataContent(certBags)
+	if err != nil {
+		return nil, err
+	}
+
+	// The private key, encrypted in a shrouded key bag.
+	pkcs8, err := marshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	encryptedKey, err := pkcs12EncryptPrivateKey(pkcs8, bmpPassword)
+	if err != nil {
+		return nil, err
+	}
+	keyContent, err := pkcs12DataContent([]pkcs12SafeBag{{ID: oidPKCS8ShroudedKeyBag, Value: pkcs12Explicit(encryptedKey), Attributes: keyAttributes}})
+	if err != nil {
+		return nil, err
+	}
+
+	authenticatedSafe, err := asn1.Marshal([]pkcs12ContentInfo{certContent, keyContent})
+	if err != nil {
+		return nil, err
+	}
+
+	macSalt, err := randomBytes(pkcs12SaltLength)
+	if err != nil {
+		return nil, err
+	}
+	macKey := pkcs12DeriveKey(bmpPassword, macSalt, pkcs12Iterations, pkcs12MACID, sha1.Size)
+	mac := hmac.New(sha1.New, macKey)
+	mac.Write(authenticatedSafe)
+
+	authSafe, err := pkcs12OctetStringContent(authenticatedSafe)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(pkcs12PFX{
+		Version:  3,
+		AuthSafe: authSafe,
+		MacData: pkcs12MacData{
+			Mac:        pkcs12DigestInfo{Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA1, Parameters: asn1NullValue}, Digest: mac.Sum(nil)},
+			MacSalt:    macSalt,
+			Iterations: pkcs12Iterations,
+		},
+	})
+}
+
+// marshalPKCS8PrivateKey encodes an RSA or an ECDSA private key in the PKCS#8 form.
+// x509.MarshalPKCS8PrivateKey requires Go 1.10.
+func marshalPKCS8PrivateKey(key crypto.Signer) ([]byte, error) {
+	var info pkcs8PrivateKeyInfo
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		info.Algorithm = pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1NullValue}
+		info.PrivateKey = x509.MarshalPKCS1PrivateKey(key)
+	case *ecdsa.PrivateKey:
+		var oid asn1.ObjectIdentifier
+		switch key.Curve {
+		case elliptic.P224():
+			oid = oidNamedCurveP224
+		case elliptic.P256():
+			oid = oidNamedCurveP256
+		case elliptic.P384():
+			oid = oidNamedCurveP384
+		case elliptic.P521():
+			oid = oidNamedCurveP521
+		default:
+			return nil, fmt.Errorf("unsupported elliptic curve %v", key.Curve.Params().Name)
+		}
+		curve, err := asn1.Marshal(oid)
+		if err != nil {
+			return nil, err
+		}
+		ecKey, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		info.Algorithm = pkix.AlgorithmIdentifier{Algorithm: oidECPublicKey, Parameters: asn1.RawValue{FullBytes: curve}}
+		info.PrivateKey = ecKey
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return asn1.Marshal(info)
+}
+
+// pkcs12DataContent returns the safe bags in a data content.
+func pkcs12DataContent(bags []pkcs12SafeBag) (pkcs12ContentInfo, error) {
+	safeContents, err := asn1.Marshal(bags)
+	if err != nil {
+		return pkcs12ContentInfo{}, err
+	}
+	return pkcs12OctetStringContent(safeContents)
+}
+
+func pkcs12OctetStringContent(data []byte) (pkcs12ContentInfo, error) {
+	content, err := asn1.Marshal(data)
+	if err != nil {
+		return pkcs12ContentInfo{}, err
+	}
+	return pkcs12ContentInfo{ContentType: oidData, Content: pkcs12Explicit(content)}, nil
+}
+
+// pkcs12Explicit wraps a DER value in an explicit [0] tag. encoding/asn1
+// ignores the explicit parameter of the RawValue fields that have FullBytes set.
+func pkcs12Explicit(der []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der}
+}
+
+// pkcs12EncryptPrivateKey encrypts the PKCS#8 private key with PBE-SHA1-3DES.
+func pkcs12EncryptPrivateKey(pkcs8 []byte, bmpPassword []byte) ([]byte, error) {
+	salt, err := randomBytes(pkcs12SaltLength)
+	if err != nil {
+		return nil, err
+	}
+	params, err := asn1.Marshal(pkcs12PBEParams{Salt: salt, Iterations: pkcs12Iterations})
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := des.NewTripleDESCipher(pkcs12DeriveKey(bmpPassword, salt, pkcs12Iterations, pkcs12KeyID, 24))
+	if err != nil {
+		return nil, err
+	}
+	iv := pkcs12DeriveKey(bmpPassword, salt, pkcs12Iterations, pkcs12IVID, des.BlockSize)
+
+	// PKCS#7 padding.
+	padding := block.BlockSize() - len(pkcs8)%block.BlockSize()
+	encrypted := make([]byte, len(pkcs8)+padding)
+	copy(encrypted, pkcs8)
+	for i := len(pkcs8); i < len(encrypted); i++ {
+		encrypted[i] = byte(padding)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, encrypted)
+
+	return asn1.Marshal(pkcs12EncryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: oidPBEWithSHAAnd3KeyDES, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData: encrypted,
+	})
+}
+
+// pkcs12DeriveKey derives key material from the password with SHA-1,
+// as defined in RFC 7292 Appendix B.2.
+func pkcs12DeriveKey(bmpPassword []byte, salt []byte, iterations int, id byte, size int) []byte {
+	const v = sha1.BlockSize
+
+	d := make([]byte, v)
+	for j := range d {
+		d[j] = id
+	}
+	s := fillBlocks(salt, v)
+	p := fillBlocks(bmpPassword, v)
+	i := append(s, p...)
+
+	var key []byte
+	for len(key) < size {
+		h := sha1.New()
+		h.Write(d)
+		h.Write(i)
+		a := h.Sum(nil)
+		for r := 1; r < iterations; r++ {
+			sum := sha1.Sum(a)
+			a = sum[:]
+		}
+		key = append(key, a...)
+
+		// Ij = (Ij + B + 1) mod 2^(v*8) for each block of I.
+		b := fillBlocks(a, v)[:v]
+		for j := 0; j < len(i); j += v {
+			carry := 1
+			for k := v - 1; k >= 0; k-- {
+				sum := int(i[j+k]) + int(b[k]) + carry
+				i[j+k], carry = byte(sum), sum>>8
+			}
+		}
+	}
+	return key[:size]
+}
+
+// fillBlocks repeats the data to fill a whole number of blocks of size v.
+func fillBlocks(data []byte, v int) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+	filled := make([]byte, v*((len(data)+v-1)/v))
+	for i := range filled {
+		filled[i] = data[i%len(data)]
+	}
+	return filled
+}
+
+// bmpString returns the password as a null-terminated big-endian UTF-16 string.
+func bmpString(s string) ([]byte, error) {
+	var bmp []byte
+	for _, r := range s {
+		if r > 0xFFFF {
+			return nil, fmt.Errorf("password character %q is not supported by PKCS#12", r)
+		}
+	}
+	for _, c := range utf16.Encode([]rune(s)) {
+		bmp = append(bmp, byte(c>>8), byte(c))
+	}
+	return append(bmp, 0, 0), nil
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	return b, err
+}
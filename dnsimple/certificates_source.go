@@ -0,0 +1,422 @@
+package dnsimple
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCertificateRefreshInterval         = 6 * time.Hour
+	defaultCertificateExpiringRefreshInterval = 30 * time.Minute
+	defaultCertificateRefreshBefore           = 30
+)
+
+// CertificateSource serves the issued certificates of a set of domains to a tls.Config.
+//
+// The certificates are downloaded once and cached in memory, and in CacheDir when set.
+// Refresh picks up the certificates issued since the previous refresh, and Run refreshes
+// the certificates in the background, more often when a certificate nears its expiration.
+//
+//	source := dnsimple.NewCertificateSource(client, accountID, []string{"example.com"})
+//	if err := source.Refresh(); err != nil {
+//		// ...
+//	}
+//	go source.Run(ctx, nil)
+//	server := &http.Server{TLSConfig: &tls.Config{GetCertificate: source.GetCertificate}}
+type CertificateSource struct {
+	client      *Client
+	accountID   string
+	domainNames []string
+
+	// The directory where the bundles are cached, if any. The cached bundles
+	// are used when the certificates can't be listed, for instance at startup
+	// when the API is unreachable.
+	CacheDir string
+
+	// The interval between two refreshes. Defaults to 6 hours.
+	RefreshInterval time.Duration
+
+	// The interval between two refreshes when a certificate expires within
+	// RefreshBefore days, so that its renewal is picked up quickly.
+	// Defaults to 30 minutes and 30 days.
+	ExpiringRefreshInterval time.Duration
+	RefreshBefore           int
+
+	mu           sync.RWMutex
+	certificates map[string][]sourceCertificate
+
+	now func() time.Time
+}
+
+// sourceCertificate represents a certificate served by a CertificateSource.
+type sourceCertificate struct {
+	managed ManagedCertificate
+	names   []string
+	tls     *tls.Certificate
+}
+
+// NewCertificateSource returns a CertificateSource for the certificates of the domains.
+// The source is empty until the first Refresh.
+func NewCertificateSource(c *Client, accountID string, domainNames []string) *CertificateSource {
+	return &CertificateSource{
+		client:                  c,
+		accountID:               accountID,
+		domainNames:             domainNames,
+		RefreshInterval:         defaultCertificateRefreshInterval,
+		ExpiringRefreshInterval: defaultCertificateExpiringRefreshInterval,
+		RefreshBefore:           defaultCertificateRefreshBefore,
+		certificates:            map[string][]sourceCertificate{},
+		now:                     time.Now,
+	}
+}
+
+// GetCertificate returns the certificate for the server name of the handshake.
+// It implements tls.Config.GetCertificate.
+//
+// The certificates are matched against the common name and the alternate names,
+// including wildcard names. When several certificates match, the one that
+// expires last is returned.
+func (s *CertificateSource) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	serverName := strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var match *sourceCertificate
+	for _, certificates := range s.certificates {
+		for i, certificate := range certificates {
+			if !certificate.matches(serverName) {
+				continue
+			}
+			if match == nil || certificate.tls.Leaf.NotAfter.After(match.tls.Leaf.NotAfter) {
+				match = &certificates[i]
+			}
+		}
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("no certificate for %q", hello.ServerName)
+	}
+	return match.tls, nil
+}
+
+// Certificates returns the certificates currently served.
+func (s *CertificateSource) Certificates() []ManagedCertificate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var managed []ManagedCertificate
+	for _, domainName := range s.domainNames {
+		for _, certificate := range s.certificates[domainName] {
+			managed = append(managed, certificate.managed)
+		}
+	}
+	return managed
+}
+
+// Refresh lists the certificates of the domains, and downloads the ones
+// issued since the previous refresh. For each set of names, only the issued
+// certificate that expires last is served.
+//
+// When the certificates of a domain can't be refreshed, the certificates
+// previously served for the domain are kept, or loaded from CacheDir.
+// Every domain is refreshed even when one of them fails,
+// and the returned error is the first error encountered.
+func (s *CertificateSource) Refresh() error {
+	var firstErr error
+	for _, domainName := range s.domainNames {
+		if err := s.refreshDomain(domainName); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("refreshing certificates of %v: %v", domainName, err)
+			}
+
+			s.mu.RLock()
+			_, loaded := s.certificates[domainName]
+			s.mu.RUnlock()
+			if !loaded {
+				s.loadCache(domainName)
+			}
+		}
+	}
+	return firstErr
+}
+
+// Run refreshes the certificates until the context is done.
+// Refresh errors are reported to onError, if not nil, and don't stop the source.
+func (s *CertificateSource) Run(ctx context.Context, onError func(error)) error {
+	for {
+		if err := s.Refresh(); err != nil && onError != nil {
+			onError(err)
+		}
+
+		if err := sleep(ctx, s.nextRefresh()); err != nil {
+			return err
+		}
+	}
+}
+
+// nextRefresh returns the interval until the next refresh.
+func (s *CertificateSource) nextRefresh() time.Duration {
+	interval, expiringInterval, refreshBefore := s.RefreshInterval, s.ExpiringRefreshInterval, s.RefreshBefore
+	if interval <= 0 {
+		interval = defaultCertificateRefreshInterval
+	}
+	if expiringInterval <= 0 {
+		expiringInterval = defaultCertificateExpiringRefreshInterval
+	}
+	if refreshBefore <= 0 {
+		refreshBefore = defaultCertificateRefreshBefore
+	}
+	deadline := s.currentTime().AddDate(0, 0, refreshBefore)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, certificates := range s.certificates {
+		for _, certificate := range certificates {
+			if certificate.tls.Leaf.NotAfter.Before(deadline) && expiringInterval < interval {
+				return expiringInterval
+			}
+		}
+	}
+	return interval
+}
+
+// currentTime returns the current time. It falls back to time.Now for a source
+// that wasn't created with NewCertificateSource.
+func (s *CertificateSource) currentTime() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
+}
+
+func (s *CertificateSource) refreshDomain(domainName string) error {
+	listed, err := s.listCertificates(domainName)
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	current := s.certificates[domainName]
+	s.mu.RUnlock()
+	cached := s.readCache(domainName)
+
+	var certificates []sourceCertificate
+	for _, certificate := range currentCertificates(listed, s.currentTime()) {
+		var served *sourceCertificate
+		for _, candidates := range [][]sourceCertificate{current, cached} {
+			for i := range candidates {
+				if candidates[i].managed.Certificate.ID == certificate.ID {
+					served = &candidates[i]
+					break
+				}
+			}
+			if served != nil {
+				break
+			}
+		}
+
+		if served == nil {
+			bundle, err := s.download(domainName, certificate.ID)
+			if err != nil {
+				return err
+			}
+			served, err = newSourceCertificate(ManagedCertificate{Certificate: certificate, Bundle: bundle})
+			if err != nil {
+				return fmt.Errorf("certificate %v: %v", certificate.ID, err)
+			}
+		}
+		entry := *served
+		entry.managed.Certificate = certificate
+		certificates = append(certificates, entry)
+	}
+
+	s.mu.Lock()
+	if s.certificates == nil {
+		s.certificates = map[string][]sourceCertificate{}
+	}
+	s.certificates[domainName] = certificates
+	s.mu.Unlock()
+
+	return s.writeCache(domainName, certificates)
+}
+
+func (s *CertificateSource) listCertificates(domainName string) ([]Certificate, error) {
+	var certificates []Certificate
+	err := eachPage(func(options ListOptions) (*Pagination, error) {
+		certificatesResponse, err := s.client.Certificates.ListCertificates(s.accountID, domainName, &options)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificatesResponse.Data...)
+		return certificatesResponse.Pagination, nil
+	})
+	return certificates, err
+}
+
+func (s *CertificateSource) download(domainName string, certificateID int64) (*CertificateBundle, error) {
+	bundleResponse, err := s.client.Certificates.DownloadCertificate(s.accountID, domainName, certificateID)
+	if err != nil {
+		return nil, fmt.Errorf("downloading certificate %v: %v", certificateID, err)
+	}
+	bundle := bundleResponse.Data
+
+	keyResponse, err := s.client.Certificates.GetCertificatePrivateKey(s.accountID, domainName, certificateID)
+	if err != nil {
+		return nil, fmt.Errorf("getting private key of certificate %v: %v", certificateID, err)
+	}
+	bundle.PrivateKey = keyResponse.Data.PrivateKey
+
+	return bundle, nil
+}
+
+// cachePath returns the path of the cache file of the domain.
+func (s *CertificateSource) cachePath(domainName string) string {
+	return filepath.Join(s.CacheDir, strings.Replace(domainName, string(filepath.Separator), "_", -1)+".json")
+}
+
+// readCache returns the certificates cached for the domain. Invalid cached
+// certificates are ignored, and downloaded again.
+func (s *CertificateSource) readCache(domainName string) []sourceCertificate {
+	if s.CacheDir == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(s.cachePath(domainName))
+	if err != nil {
+		return nil
+	}
+	var managed []ManagedCertificate
+	if err := json.Unmarshal(data, &managed); err != nil {
+		return nil
+	}
+
+	var certificates []sourceCertificate
+	for _, m := range managed {
+		if m.Certificate == nil || m.Bundle == nil {
+			continue
+		}
+		if certificate, err := newSourceCertificate(m); err == nil {
+			certificates = append(certificates, *certificate)
+		}
+	}
+	return certificates
+}
+
+// loadCache serves the certificates cached for the domain that are not expired.
+func (s *CertificateSource) loadCache(domainName string) {
+	var certificates []sourceCertificate
+	for _, certificate := range s.readCache(domainName) {
+		if certificate.tls.Leaf.NotAfter.After(s.currentTime()) {
+			certificates = append(certificates, certificate)
+		}
+	}
+	if len(certificates) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	if s.certificates == nil {
+		s.certificates = map[string][]sourceCertificate{}
+	}
+	s.certificates[domainName] = certificates
+	s.mu.Unlock()
+}
+
+func (s *CertificateSource) writeCache(domainName string, certificates []sourceCertificate) error {
+	if s.CacheDir == "" {
+		return nil
+	}
+
+	managed := make([]ManagedCertificate, len(certificates))
+	for i, certificate := range certificates {
+		managed[i] = certificate.managed
+	}
+	data, err := json.MarshalIndent(managed, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// The cache contains the private keys.
+	if err := os.MkdirAll(s.CacheDir, 0700); err != nil {
+		return err
+	}
+	return writeFileAtomic(s.cachePath(domainName), data, 0600)
+}
+
+func newSourceCertificate(managed ManagedCertificate) (*sourceCertificate, error) {
+	certificate, err := managed.Bundle.TLSCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{strings.ToLower(managed.Certificate.CommonName)}
+	for _, name := range managed.Certificate.AlternateNames {
+		names = append(names, strings.ToLower(name))
+	}
+	return &sourceCertificate{managed: managed, names: names, tls: &certificate}, nil
+}
+
+// matches returns true if one of the names of the certificate matches the server name.
+// A wildcard name matches a single label.
+func (c *sourceCertificate) matches(serverName string) bool {
+	for _, name := range c.names {
+		if name == serverName {
+			return true
+		}
+		if strings.HasPrefix(name, "*.") {
+			if i := strings.Index(serverName, "."); i > 0 && serverName[i+1:] == name[2:] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// currentCertificates returns, for each set of names, the issued certificate that expires last.
+func currentCertificates(certificates []Certificate, now time.Time) []*Certificate {
+	latest := map[string]*Certificate{}
+	expirations := map[string]time.Time{}
+	var keys []string
+
+	for i := range certificates {
+		certificate := &certificates[i]
+		if certificate.State != "issued" {
+			continue
+		}
+		expiresOn, err := certificate.ExpiresOnTime()
+		if err != nil || (!expiresOn.IsZero() && expiresOn.Before(now)) {
+			continue
+		}
+
+		names := []string{strings.ToLower(certificate.CommonName)}
+		for _, name := range certificate.AlternateNames {
+			names = append(names, strings.ToLower(name))
+		}
+		sort.Strings(names[1:])
+		key := strings.Join(names, ",")
+
+		if previous, ok := latest[key]; !ok {
+			keys = append(keys, key)
+		} else if !expiresOn.After(expirations[key]) && !(expiresOn.Equal(expirations[key]) && certificate.ID > previous.ID) {
+			continue
+		}
+		latest[key], expirations[key] = certificate, expiresOn
+	}
+
+	current := make([]*Certificate, len(keys))
+	for i, key := range keys {
+		current[i] = latest[key]
+	}
+	return current
+}
@@ -0,0 +1,271 @@
+package dnsimple
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// certificateSourceFixtures simulates the certificates of example.com, with generated bundles.
+type certificateSourceFixtures struct {
+	mu           sync.Mutex
+	certificates []Certificate
+	bundles      map[int64]*CertificateBundle
+	unavailable  bool
+
+	downloads []int64
+}
+
+func setupCertificateSourceFixtures(t *testing.T) *certificateSourceFixtures {
+	f := &certificateSourceFixtures{bundles: map[int64]*CertificateBundle{}}
+
+	mux.HandleFunc("/v2/1010/domains/example.com/certificates", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if f.unavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeData(w, r, http.StatusOK, f.certificates)
+	})
+
+	mux.HandleFunc("/v2/1010/domains/example.com/certificates/", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v2/1010/domains/example.com/certificates/"), "/")
+		id, _ := strconv.ParseInt(parts[0], 10, 64)
+		bundle := f.bundles[id]
+		if f.unavailable || bundle == nil || len(parts) != 2 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch parts[1] {
+		case "download":
+			f.downloads = append(f.downloads, id)
+			writeData(w, r, http.StatusOK, CertificateBundle{ServerCertificate: bundle.ServerCertificate, RootCertificate: bundle.RootCertificate, IntermediateCertificates: bundle.IntermediateCertificates})
+		case "private_key":
+			writeData(w, r, http.StatusOK, CertificateBundle{PrivateKey: bundle.PrivateKey})
+		default:
+			t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+		}
+	})
+
+	return f
+}
+
+func (f *certificateSourceFixtures) add(t *testing.T, certificate Certificate) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.certificates = append(f.certificates, certificate)
+	f.bundles[certificate.ID] = newTestCertificateBundle(t)
+}
+
+func serveCertificate(t *testing.T, source *CertificateSource, serverName string) int64 {
+	certificate, err := source.GetCertificate(&tls.ClientHelloInfo{ServerName: serverName})
+	if err != nil {
+		t.Fatalf("CertificateSource.GetCertificate(%v) returned error: %v", serverName, err)
+	}
+
+	for _, managed := range source.Certificates() {
+		served, _ := managed.Bundle.TLSCertificate()
+		if string(served.Certificate[0]) == string(certificate.Certificate[0]) {
+			return managed.Certificate.ID
+		}
+	}
+	t.Fatalf("CertificateSource.GetCertificate(%v) returned an unknown certificate", serverName)
+	return 0
+}
+
+func TestCertificateSource_GetCertificate(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupCertificateSourceFixtures(t)
+	expiresOn := time.Now().AddDate(0, 2, 0).Format("2006-01-02")
+	f.add(t, Certificate{ID: 1, CommonName: "www.example.com", AlternateNames: []string{"example.com"}, State: "issued", ExpiresOn: time.Now().AddDate(0, 1, 0).Format("2006-01-02")})
+	f.add(t, Certificate{ID: 2, CommonName: "www.example.com", AlternateNames: []string{"example.com"}, State: "issued", ExpiresOn: expiresOn})
+	f.add(t, Certificate{ID: 3, CommonName: "*.api.example.com", State: "issued", ExpiresOn: expiresOn})
+	f.add(t, Certificate{ID: 4, CommonName: "old.example.com", State: "issued", ExpiresOn: "2016-01-01"})
+	f.add(t, Certificate{ID: 5, CommonName: "new.example.com", State: "requesting"})
+
+	source := NewCertificateSource(client, "1010", []string{"example.com"})
+	if err := source.Refresh(); err != nil {
+		t.Fatalf("CertificateSource.Refresh() returned error: %v", err)
+	}
+
+	if want, got := int64(2), serveCertificate(t, source, "WWW.example.com."); want != got {
+		t.Errorf("CertificateSource.GetCertificate() returned certificate `%v`, expected `%v`", got, want)
+	}
+	if want, got := int64(2), serveCertificate(t, source, "example.com"); want != got {
+		t.Errorf("CertificateSource.GetCertificate() returned certificate `%v`, expected `%v`", got, want)
+	}
+	if want, got := int64(3), serveCertificate(t, source, "v1.api.example.com"); want != got {
+		t.Errorf("CertificateSource.GetCertificate() returned certificate `%v`, expected `%v`", got, want)
+	}
+	for _, serverName := range []string{"api.example.com", "a.b.api.example.com", "old.example.com", "new.example.com", ""} {
+		if _, err := source.GetCertificate(&tls.ClientHelloInfo{ServerName: serverName}); err == nil {
+			t.Errorf("CertificateSource.GetCertificate(%v) expected to return an error", serverName)
+		}
+	}
+
+	if want, got := []int64{2, 3}, f.downloads; !reflect.DeepEqual(want, got) {
+		t.Errorf("CertificateSource.Refresh() downloaded `%v`, expected `%v`", got, want)
+	}
+}
+
+func TestCertificateSource_Refresh(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupCertificateSourceFixtures(t)
+	f.add(t, Certificate{ID: 1, CommonName: "www.example.com", State: "issued", ExpiresOn: time.Now().AddDate(0, 0, 10).Format("2006-01-02")})
+
+	source := NewCertificateSource(client, "1010", []string{"example.com"})
+	if err := source.Refresh(); err != nil {
+		t.Fatalf("CertificateSource.Refresh() returned error: %v", err)
+	}
+
+	// The renewal is picked up, and the certificates already served are not downloaded again.
+	f.add(t, Certificate{ID: 2, CommonName: "www.example.com", State: "issued", ExpiresOn: time.Now().AddDate(0, 3, 0).Format("2006-01-02")})
+	if err := source.Refresh(); err != nil {
+		t.Fatalf("CertificateSource.Refresh() returned error: %v", err)
+	}
+	if err := source.Refresh(); err != nil {
+		t.Fatalf("CertificateSource.Refresh() returned error: %v", err)
+	}
+
+	if want, got := int64(2), serveCertificate(t, source, "www.example.com"); want != got {
+		t.Errorf("CertificateSource.GetCertificate() returned certificate `%v`, expected `%v`", got, want)
+	}
+	if want, got := []int64{1, 2}, f.downloads; !reflect.DeepEqual(want, got) {
+		t.Errorf("CertificateSource.Refresh() downloaded `%v`, expected `%v`", got, want)
+	}
+
+	// The certificates are kept when the API is unavailable.
+	f.unavailable = true
+	if err := source.Refresh(); err == nil {
+		t.Errorf("CertificateSource.Refresh() expected to return an error")
+	}
+	if want, got := int64(2), serveCertificate(t, source, "www.example.com"); want != got {
+		t.Errorf("CertificateSource.GetCertificate() returned certificate `%v`, expected `%v`", got, want)
+	}
+}
+
+func TestCertificateSource_CacheDir(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	dir, err := ioutil.TempDir("", "dnsimple-certificates")
+	if err != nil {
+		t.Fatalf("TempDir() returned error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	cacheDir := filepath.Join(dir, "cache")
+
+	f := setupCertificateSourceFixtures(t)
+	f.add(t, Certificate{ID: 1, CommonName: "www.example.com", State: "issued", ExpiresOn: time.Now().AddDate(0, 1, 0).Format("2006-01-02")})
+
+	source := NewCertificateSource(client, "1010", []string{"example.com"})
+	source.CacheDir = cacheDir
+	if err := source.Refresh(); err != nil {
+		t.Fatalf("CertificateSource.Refresh() returned error: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(cacheDir, "example.com.json"))
+	if err != nil {
+		t.Fatalf("CertificateSource.Refresh() expected to write the cache: %v", err)
+	}
+	if want, got := os.FileMode(0600), info.Mode().Perm(); want != got {
+		t.Errorf("CertificateSource.Refresh() cache mode expected to be `%v`, got `%v`", want, got)
+	}
+
+	// A new source uses the cache instead of downloading the certificate again.
+	restarted := NewCertificateSource(client, "1010", []string{"example.com"})
+	restarted.CacheDir = cacheDir
+	if err := restarted.Refresh(); err != nil {
+		t.Fatalf("CertificateSource.Refresh() returned error: %v", err)
+	}
+	if want, got := []int64{1}, f.downloads; !reflect.DeepEqual(want, got) {
+		t.Errorf("CertificateSource.Refresh() downloaded `%v`, expected `%v`", got, want)
+	}
+
+	// A new source serves the cache when the API is unavailable.
+	f.unavailable = true
+	offline := NewCertificateSource(client, "1010", []string{"example.com"})
+	offline.CacheDir = cacheDir
+	if err := offline.Refresh(); err == nil {
+		t.Errorf("CertificateSource.Refresh() expected to return an error")
+	}
+	if want, got := int64(1), serveCertificate(t, offline, "www.example.com"); want != got {
+		t.Errorf("CertificateSource.GetCertificate() returned certificate `%v`, expected `%v`", got, want)
+	}
+}
+
+func TestCertificateSource_ZeroValue(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	dir, err := ioutil.TempDir("", "dnsimple-certificates")
+	if err != nil {
+		t.Fatalf("TempDir() returned error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := setupCertificateSourceFixtures(t)
+	f.add(t, Certificate{ID: 1, CommonName: "www.example.com", State: "issued", ExpiresOn: time.Now().AddDate(0, 1, 0).Format("2006-01-02")})
+
+	source := &CertificateSource{client: client, accountID: "1010", domainNames: []string{"example.com"}, CacheDir: dir}
+	if err := source.Refresh(); err != nil {
+		t.Fatalf("CertificateSource.Refresh() returned error: %v", err)
+	}
+	if want, got := int64(1), serveCertificate(t, source, "www.example.com"); want != got {
+		t.Errorf("CertificateSource.GetCertificate() returned certificate `%v`, expected `%v`", got, want)
+	}
+	if want, got := defaultCertificateExpiringRefreshInterval, source.nextRefresh(); want != got {
+		t.Errorf("CertificateSource.nextRefresh() expected to be `%v`, got `%v`", want, got)
+	}
+
+	f.unavailable = true
+	offline := &CertificateSource{client: client, accountID: "1010", domainNames: []string{"example.com"}, CacheDir: dir}
+	if err := offline.Refresh(); err == nil {
+		t.Errorf("CertificateSource.Refresh() expected to return an error")
+	}
+	if want, got := int64(1), serveCertificate(t, offline, "www.example.com"); want != got {
+		t.Errorf("CertificateSource.GetCertificate() returned certificate `%v`, expected `%v`", got, want)
+	}
+}
+
+func TestCertificateSource_nextRefresh(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupCertificateSourceFixtures(t)
+	f.add(t, Certificate{ID: 1, CommonName: "www.example.com", State: "issued"})
+
+	source := NewCertificateSource(client, "1010", []string{"example.com"})
+	if err := source.Refresh(); err != nil {
+		t.Fatalf("CertificateSource.Refresh() returned error: %v", err)
+	}
+
+	// The generated certificates expire in one hour.
+	if want, got := defaultCertificateExpiringRefreshInterval, source.nextRefresh(); want != got {
+		t.Errorf("CertificateSource.nextRefresh() expected to be `%v`, got `%v`", want, got)
+	}
+
+	source.now = func() time.Time { return time.Now().AddDate(0, 0, -60) }
+	if want, got := defaultCertificateRefreshInterval, source.nextRefresh(); want != got {
+		t.Errorf("CertificateSource.nextRefresh() expected to be `%v`, got `%v`", want, got)
+	}
+}
@@ -203,6 +203,9 @@ func TestCertificates_LetsencryptPurchase(t *testing.T) {
 	if want, got := int64(300), certificatePurchase.ID; want != got {
 		t.Fatalf("Certificates.PurchaseLetsencryptCertificate() returned ID expected to be `%v`, got `%v`", want, got)
 	}
+	if want, got := int64(300), certificatePurchase.CertificateID; want != got {
+		t.Fatalf("Certificates.PurchaseLetsencryptCertificate() returned CertificateID expected to be `%v`, got `%v`", want, got)
+	}
 }
 
 func TestCertificates_LetsencryptPurchaseWithAttributes(t *testing.T) {
@@ -4,6 +4,7 @@ package dnsimple
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -272,6 +273,55 @@ type Pagination struct {
 	TotalEntries int `json:"total_entries"`
 }
 
+// maxPerPage is the maximum number of entries the API returns in a single page.
+const maxPerPage = 100
+
+// eachPage calls fn for every page of a paginated collection, starting from the first page,
+// until fn returns an error or the pagination reports there are no more pages.
+func eachPage(fn func(options ListOptions) (*Pagination, error)) error {
+	for page := 1; ; page++ {
+		pagination, err := fn(ListOptions{Page: page, PerPage: maxPerPage})
+		if err != nil {
+			return err
+		}
+		if pagination == nil || pagination.CurrentPage >= pagination.TotalPages {
+			return nil
+		}
+	}
+}
+
+// optionalJSON buffers the body of a response that is either a JSON document
+// or empty, for the API methods that may respond with no content.
+// Pass it as the obj of a request, then decode it.
+type optionalJSON struct {
+	bytes.Buffer
+}
+
+// decode decodes the buffered body into obj, unless the body is empty.
+func (b *optionalJSON) decode(obj interface{}) error {
+	if b.Len() == 0 {
+		return nil
+	}
+	return json.Unmarshal(b.Bytes(), obj)
+}
+
+// sleep pauses for the given duration, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // An ErrorResponse represents an API response that generated an error.
 type ErrorResponse struct {
 	Response
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -109,6 +111,51 @@ func httpResponseFixture(t *testing.T, filename string) *http.Response {
 	return resp
 }
 
+// serveFixture registers a handler on the mock server that replies to path with the given HTTP fixture.
+func serveFixture(t *testing.T, path, filename string) {
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, filename)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+}
+
+// testPerPage is the number of entries per page of the lists written by writeData.
+// It is small, so that the tests go through several pages.
+const testPerPage = 2
+
+// writeData writes data as the response of an API request.
+// A slice is paginated like the API does, by testPerPage entries,
+// according to the page query parameter of the request.
+func writeData(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	response := map[string]interface{}{"data": data}
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 1 {
+			page = 1
+		}
+		totalPages := (v.Len() + testPerPage - 1) / testPerPage
+		if totalPages == 0 {
+			totalPages = 1
+		}
+
+		start, end := (page-1)*testPerPage, page*testPerPage
+		if start > v.Len() {
+			start = v.Len()
+		}
+		if end > v.Len() {
+			end = v.Len()
+		}
+		response["data"] = v.Slice(start, end).Interface()
+		response["pagination"] = Pagination{CurrentPage: page, PerPage: testPerPage, TotalPages: totalPages, TotalEntries: v.Len()}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
 func TestNewClient(t *testing.T) {
 	c := NewClient(http.DefaultClient)
 
@@ -167,3 +214,36 @@ func TestClient_NewRequest_WithBody(t *testing.T) {
 		t.Errorf("NewRequest with body expected error with blank string")
 	}
 }
+
+func TestEachPage(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/domains", func(w http.ResponseWriter, r *http.Request) {
+		testQuery(t, r, url.Values{"page": []string{r.URL.Query().Get("page")}, "per_page": []string{"100"}})
+
+		httpResponse := httpResponseFixture(t, fmt.Sprintf("/api/pages-%vof3.http", r.URL.Query().Get("page")))
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	var ids []int64
+	err := eachPage(func(options ListOptions) (*Pagination, error) {
+		domainsResponse, err := client.Domains.ListDomains("1010", &DomainListOptions{ListOptions: options})
+		if err != nil {
+			return nil, err
+		}
+		for _, domain := range domainsResponse.Data {
+			ids = append(ids, domain.ID)
+		}
+		return domainsResponse.Pagination, nil
+	})
+	if err != nil {
+		t.Fatalf("eachPage() returned error: %v", err)
+	}
+
+	if want := []int64{1, 2, 3, 4, 5}; !reflect.DeepEqual(want, ids) {
+		t.Errorf("eachPage() visited %v, want %v", ids, want)
+	}
+}
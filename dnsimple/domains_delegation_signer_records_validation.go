@@ -0,0 +1,129 @@
+package dnsimple
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dnssecAlgorithms are the DNSSEC algorithm numbers of the IANA registry
+// that can be used to sign a zone, along with their mnemonics.
+//
+// See https://www.iana.org/assignments/dns-sec-alg-numbers/
+var dnssecAlgorithms = map[uint8]string{
+	1:   "RSAMD5",
+	3:   "DSA",
+	5:   "RSASHA1",
+	6:   "DSA-NSEC3-SHA1",
+	7:   "RSASHA1-NSEC3-SHA1",
+	8:   "RSASHA256",
+	10:  "RSASHA512",
+	12:  "ECC-GOST",
+	13:  "ECDSAP256SHA256",
+	14:  "ECDSAP384SHA384",
+	15:  "ED25519",
+	16:  "ED448",
+	17:  "SM2SM3",
+	23:  "ECC-GOST12",
+	253: "PRIVATEDNS",
+	254: "PRIVATEOID",
+}
+
+// dsDigestTypes are the DS digest types of the IANA registry, along with
+// their mnemonics and the length of their digest in bytes.
+//
+// See https://www.iana.org/assignments/ds-rr-types/
+var dsDigestTypes = map[uint8]struct {
+	name   string
+	length int
+}{
+	1: {"SHA-1", 20},
+	2: {"SHA-256", 32},
+	3: {"GOST R 34.11-94", 32},
+	4: {"SHA-384", 48},
+	5: {"GOST R 34.11-2012", 32},
+	6: {"SM3", 32},
+}
+
+// DelegationSignerRecordError represents an invalid delegation signer record.
+type DelegationSignerRecordError struct {
+	// The invalid attribute: keytag, algorithm, digest_type or digest.
+	Attribute string
+	Message   string
+}
+
+// Error implements the error interface.
+func (e *DelegationSignerRecordError) Error() string {
+	return fmt.Sprintf("invalid DS record %v: %v", e.Attribute, e.Message)
+}
+
+// ValidateDelegationSignerRecord checks the attributes of a delegation signer record:
+// the key tag must be a 16-bit number, the algorithm and the digest type must be
+// registered at the IANA, and the digest must be hexadecimal with the length
+// of the digest type.
+//
+// The returned error is a *DelegationSignerRecordError.
+func ValidateDelegationSignerRecord(record DelegationSignerRecord) error {
+	if _, err := strconv.ParseUint(strings.TrimSpace(record.Keytag), 10, 16); err != nil {
+		return &DelegationSignerRecordError{Attribute: "keytag", Message: fmt.Sprintf("%q is not a number between 0 and 65535", record.Keytag)}
+	}
+
+	algorithm, err := strconv.ParseUint(strings.TrimSpace(record.Algorithm), 10, 8)
+	if err != nil {
+		return &DelegationSignerRecordError{Attribute: "algorithm", Message: fmt.Sprintf("%q is not a number", record.Algorithm)}
+	}
+	if _, ok := dnssecAlgorithms[uint8(algorithm)]; !ok {
+		return &DelegationSignerRecordError{Attribute: "algorithm", Message: fmt.Sprintf("%v is not a DNSSEC signing algorithm", algorithm)}
+	}
+
+	digestType, err := strconv.ParseUint(strings.TrimSpace(record.DigestType), 10, 8)
+	if err != nil {
+		return &DelegationSignerRecordError{Attribute: "digest_type", Message: fmt.Sprintf("%q is not a number", record.DigestType)}
+	}
+	definition, ok := dsDigestTypes[uint8(digestType)]
+	if !ok {
+		return &DelegationSignerRecordError{Attribute: "digest_type", Message: fmt.Sprintf("%v is not a DS digest type", digestType)}
+	}
+
+	digest, err := hex.DecodeString(strings.Join(strings.Fields(record.Digest), ""))
+	if err != nil {
+		return &DelegationSignerRecordError{Attribute: "digest", Message: "is not hexadecimal"}
+	}
+	if len(digest) != definition.length {
+		return &DelegationSignerRecordError{Attribute: "digest", Message: fmt.Sprintf("is %v bytes long, %v digests are %v bytes long", len(digest), definition.name, definition.length)}
+	}
+
+	return nil
+}
+
+// VerifyDelegationSignerRecord checks that a delegation signer record is valid
+// and references the DNSKEY of the zone: the key tag and the algorithm must match
+// the key, and the digest must match the digest computed from the key.
+//
+// Use it before DomainsService.CreateDelegationSignerRecord: a DS record that doesn't
+// match the key of the zone breaks the resolution of the domain for validating resolvers.
+// Only SHA-1, SHA-256 and SHA-384 digests can be verified.
+func VerifyDelegationSignerRecord(record DelegationSignerRecord, key DNSKEY, zoneName string) error {
+	if err := ValidateDelegationSignerRecord(record); err != nil {
+		return err
+	}
+
+	digestType, _ := strconv.ParseUint(strings.TrimSpace(record.DigestType), 10, 8)
+	expected, err := key.DelegationSignerRecord(zoneName, uint8(digestType))
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(record.Keytag) != expected.Keytag {
+		return &DelegationSignerRecordError{Attribute: "keytag", Message: fmt.Sprintf("%v doesn't match the key tag %v of the key", record.Keytag, expected.Keytag)}
+	}
+	if strings.TrimSpace(record.Algorithm) != expected.Algorithm {
+		return &DelegationSignerRecordError{Attribute: "algorithm", Message: fmt.Sprintf("%v doesn't match the algorithm %v of the key", record.Algorithm, expected.Algorithm)}
+	}
+	if !sameDelegationSignerRecord(record, *expected) {
+		return &DelegationSignerRecordError{Attribute: "digest", Message: "doesn't match the digest of the key"}
+	}
+
+	return nil
+}
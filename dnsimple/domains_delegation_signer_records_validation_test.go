@@ -0,0 +1,72 @@
+package dnsimple
+
+import (
+	"testing"
+)
+
+func TestValidateDelegationSignerRecord(t *testing.T) {
+	valid := DelegationSignerRecord{Algorithm: "5", Digest: "2BB183AF5F22588179A53B0A98631FAD1A292118", DigestType: "1", Keytag: "60485"}
+	if err := ValidateDelegationSignerRecord(valid); err != nil {
+		t.Errorf("ValidateDelegationSignerRecord() returned error: %v", err)
+	}
+
+	tests := []struct {
+		record    DelegationSignerRecord
+		attribute string
+	}{
+		{DelegationSignerRecord{Algorithm: "5", Digest: valid.Digest, DigestType: "1", Keytag: "65536"}, "keytag"},
+		{DelegationSignerRecord{Algorithm: "RSASHA1", Digest: valid.Digest, DigestType: "1", Keytag: "60485"}, "algorithm"},
+		{DelegationSignerRecord{Algorithm: "9", Digest: valid.Digest, DigestType: "1", Keytag: "60485"}, "algorithm"},
+		{DelegationSignerRecord{Algorithm: "5", Digest: valid.Digest, DigestType: "7", Keytag: "60485"}, "digest_type"},
+		{DelegationSignerRecord{Algorithm: "5", Digest: valid.Digest, DigestType: "2", Keytag: "60485"}, "digest"},
+		{DelegationSignerRecord{Algorithm: "5", Digest: "2BB183AF5F22588179A53B0A98631FAD1A29211G", DigestType: "1", Keytag: "60485"}, "digest"},
+	}
+
+	for _, tt := range tests {
+		err := ValidateDelegationSignerRecord(tt.record)
+		recordErr, ok := err.(*DelegationSignerRecordError)
+		if !ok {
+			t.Errorf("ValidateDelegationSignerRecord(%+v) expected to return a *DelegationSignerRecordError, got `%v`", tt.record, err)
+			continue
+		}
+		if want, got := tt.attribute, recordErr.Attribute; want != got {
+			t.Errorf("ValidateDelegationSignerRecord(%+v) returned Attribute expected to be `%v`, got `%v`", tt.record, want, got)
+		}
+	}
+}
+
+func TestVerifyDelegationSignerRecord(t *testing.T) {
+	key, _ := ParseDNSKEY(rfcDNSKEY)
+
+	record := DelegationSignerRecord{Algorithm: "5", Digest: "d4b7d520e7bb5f0f67674a0cceb1e3e0614b93c4f9e99b8383f6a1e4469da50a", DigestType: "2", Keytag: "60485"}
+	if err := VerifyDelegationSignerRecord(record, *key, "dskey.example.com"); err != nil {
+		t.Errorf("VerifyDelegationSignerRecord() returned error: %v", err)
+	}
+
+	tests := []struct {
+		record    DelegationSignerRecord
+		zoneName  string
+		attribute string
+	}{
+		{DelegationSignerRecord{Algorithm: "5", Digest: record.Digest, DigestType: "2", Keytag: "60484"}, "dskey.example.com", "keytag"},
+		{DelegationSignerRecord{Algorithm: "8", Digest: record.Digest, DigestType: "2", Keytag: "60485"}, "dskey.example.com", "algorithm"},
+		{record, "example.com", "digest"},
+	}
+
+	for _, tt := range tests {
+		err := VerifyDelegationSignerRecord(tt.record, *key, tt.zoneName)
+		recordErr, ok := err.(*DelegationSignerRecordError)
+		if !ok {
+			t.Errorf("VerifyDelegationSignerRecord(%+v) expected to return a *DelegationSignerRecordError, got `%v`", tt.record, err)
+			continue
+		}
+		if want, got := tt.attribute, recordErr.Attribute; want != got {
+			t.Errorf("VerifyDelegationSignerRecord(%+v) returned Attribute expected to be `%v`, got `%v`", tt.record, want, got)
+		}
+	}
+
+	gost := DelegationSignerRecord{Algorithm: "5", Digest: record.Digest, DigestType: "3", Keytag: "60485"}
+	if err := VerifyDelegationSignerRecord(gost, *key, "dskey.example.com"); err == nil {
+		t.Errorf("VerifyDelegationSignerRecord() expected to return an error for an unsupported digest type")
+	}
+}
@@ -28,7 +28,7 @@ func (s *DomainsService) EnableDnssec(accountID string, domainIdentifier string)
 	path := versioned(dnssecPath(accountID, domainIdentifier))
 	dnssecResponse := &dnssecResponse{}
 
-	resp, err := s.client.post(path, dnssecResponse, nil)
+	resp, err := s.client.post(path, nil, dnssecResponse)
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +44,7 @@ func (s *DomainsService) DisableDnssec(accountID string, domainIdentifier string
 	path := versioned(dnssecPath(accountID, domainIdentifier))
 	dnssecResponse := &dnssecResponse{}
 
-	resp, err := s.client.delete(path, dnssecResponse, nil)
+	resp, err := s.client.delete(path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
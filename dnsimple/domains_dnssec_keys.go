@@ -0,0 +1,174 @@
+package dnsimple
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// dnskeyFlagSEP is the Secure Entry Point flag of a DNSKEY,
+// set on the key signing keys referenced by the DS records.
+const dnskeyFlagSEP = 1
+
+// DNSKEY represents a DNSKEY record of a signed zone.
+type DNSKEY struct {
+	Flags     uint16
+	Protocol  uint8
+	Algorithm uint8
+
+	// The public key, base64 encoded.
+	PublicKey string
+}
+
+// ParseDNSKEY parses the content of a DNSKEY record, in the presentation
+// format used by the zone records: "257 3 13 <base64 public key>".
+func ParseDNSKEY(content string) (*DNSKEY, error) {
+	fields := strings.Fields(content)
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("invalid DNSKEY %q: expected flags, protocol, algorithm and public key", content)
+	}
+
+	flags, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DNSKEY flags %q", fields[0])
+	}
+	protocol, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DNSKEY protocol %q", fields[1])
+	}
+	algorithm, err := strconv.ParseUint(fields[2], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DNSKEY algorithm %q", fields[2])
+	}
+
+	// The public key may be split in several blocks.
+	key := &DNSKEY{Flags: uint16(flags), Protocol: uint8(protocol), Algorithm: uint8(algorithm), PublicKey: strings.Join(fields[3:], "")}
+	if _, err := key.rdata(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// String returns the DNSKEY in presentation format.
+func (k *DNSKEY) String() string {
+	return fmt.Sprintf("%d %d %d %s", k.Flags, k.Protocol, k.Algorithm, k.PublicKey)
+}
+
+// IsKeySigningKey returns true if the key has the Secure Entry Point flag,
+// which identifies the keys that DS records should reference.
+func (k *DNSKEY) IsKeySigningKey() bool {
+	return k.Flags&dnskeyFlagSEP != 0
+}
+
+// rdata returns the wire format of the DNSKEY RDATA.
+func (k *DNSKEY) rdata() ([]byte, error) {
+	publicKey, err := base64.StdEncoding.DecodeString(k.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DNSKEY public key: %v", err)
+	}
+
+	rdata := make([]byte, 4, 4+len(publicKey))
+	binary.BigEndian.PutUint16(rdata, k.Flags)
+	rdata[2], rdata[3] = k.Protocol, k.Algorithm
+	return append(rdata, publicKey...), nil
+}
+
+// KeyTag returns the key tag of the DNSKEY, as defined in RFC 4034 Appendix B.
+func (k *DNSKEY) KeyTag() (uint16, error) {
+	rdata, err := k.rdata()
+	if err != nil {
+		return 0, err
+	}
+
+	// RSA/MD5 keys use the last bytes of the modulus as key tag.
+	if k.Algorithm == 1 {
+		if len(rdata) < 4+3 {
+			return 0, fmt.Errorf("invalid DNSKEY public key: too short")
+		}
+		return binary.BigEndian.Uint16(rdata[len(rdata)-3:]), nil
+	}
+
+	var ac uint32
+	for i, b := range rdata {
+		if i&1 == 0 {
+			ac += uint32(b) << 8
+		} else {
+			ac += uint32(b)
+		}
+	}
+	ac += ac >> 16 & 0xffff
+	return uint16(ac & 0xffff), nil
+}
+
+// DelegationSignerRecord computes the DS record of the DNSKEY for the zone,
+// using the digest type (1 for SHA-1, 2 for SHA-256, 4 for SHA-384).
+func (k *DNSKEY) DelegationSignerRecord(zoneName string, digestType uint8) (*DelegationSignerRecord, error) {
+	var h hash.Hash
+	switch digestType {
+	case 1:
+		h = sha1.New()
+	case 2:
+		h = sha256.New()
+	case 4:
+		h = sha512.New384()
+	default:
+		return nil, fmt.Errorf("unsupported DS digest type %d", digestType)
+	}
+
+	owner, err := canonicalWireName(zoneName)
+	if err != nil {
+		return nil, err
+	}
+	rdata, err := k.rdata()
+	if err != nil {
+		return nil, err
+	}
+	keyTag, err := k.KeyTag()
+	if err != nil {
+		return nil, err
+	}
+
+	h.Write(owner)
+	h.Write(rdata)
+
+	return &DelegationSignerRecord{
+		Algorithm:  strconv.Itoa(int(k.Algorithm)),
+		Digest:     strings.ToUpper(hex.EncodeToString(h.Sum(nil))),
+		DigestType: strconv.Itoa(int(digestType)),
+		Keytag:     strconv.Itoa(int(keyTag)),
+	}, nil
+}
+
+// canonicalWireName returns the name in the canonical wire format
+// of RFC 4034 section 6.2: lowercase, length-prefixed labels.
+func canonicalWireName(name string) ([]byte, error) {
+	name = normalizeHostName(name)
+
+	var wire []byte
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if len(label) == 0 || len(label) > 63 {
+				return nil, fmt.Errorf("invalid name %q", name)
+			}
+			wire = append(wire, byte(len(label)))
+			wire = append(wire, label...)
+		}
+	}
+	return append(wire, 0), nil
+}
+
+// sameDelegationSignerRecord returns true if the records have
+// the same key tag, algorithm, digest type and digest.
+func sameDelegationSignerRecord(a, b DelegationSignerRecord) bool {
+	return strings.TrimSpace(a.Keytag) == strings.TrimSpace(b.Keytag) &&
+		strings.TrimSpace(a.Algorithm) == strings.TrimSpace(b.Algorithm) &&
+		strings.TrimSpace(a.DigestType) == strings.TrimSpace(b.DigestType) &&
+		strings.EqualFold(strings.Join(strings.Fields(a.Digest), ""), strings.Join(strings.Fields(b.Digest), ""))
+}
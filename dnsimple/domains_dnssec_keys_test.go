@@ -0,0 +1,83 @@
+package dnsimple
+
+import (
+	"strconv"
+	"testing"
+)
+
+// The DNSKEY of the examples in RFC 4034 section 5.4 and RFC 4509 section 2.3.
+const rfcDNSKEY = "256 3 5 AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/2pHm822aJ5iI9BMzNXxeYCmZ DRD99WYwYqUSdjMmmAphXdvxegXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2wwjM9XzcnOf+EPbtG9DMBmADjFDc2w/rljwvFw=="
+
+func TestParseDNSKEY(t *testing.T) {
+	key, err := ParseDNSKEY(rfcDNSKEY)
+	if err != nil {
+		t.Fatalf("ParseDNSKEY() returned error: %v", err)
+	}
+
+	if want, got := uint16(256), key.Flags; want != got {
+		t.Errorf("ParseDNSKEY() returned Flags expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := uint8(5), key.Algorithm; want != got {
+		t.Errorf("ParseDNSKEY() returned Algorithm expected to be `%v`, got `%v`", want, got)
+	}
+	if key.IsKeySigningKey() {
+		t.Errorf("ParseDNSKEY() returned a key signing key, expected a zone signing key")
+	}
+
+	for _, content := range []string{"", "257 3 8", "257 3 x AQID", "257 3 8 not-base64!"} {
+		if _, err := ParseDNSKEY(content); err == nil {
+			t.Errorf("ParseDNSKEY(%q) expected to return an error", content)
+		}
+	}
+}
+
+func TestDNSKEY_KeyTag(t *testing.T) {
+	key, _ := ParseDNSKEY(rfcDNSKEY)
+
+	keyTag, err := key.KeyTag()
+	if err != nil {
+		t.Fatalf("DNSKEY.KeyTag() returned error: %v", err)
+	}
+	if want, got := uint16(60485), keyTag; want != got {
+		t.Errorf("DNSKEY.KeyTag() expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestDNSKEY_DelegationSignerRecord(t *testing.T) {
+	key, _ := ParseDNSKEY(rfcDNSKEY)
+
+	tests := []struct {
+		digestType uint8
+		digest     string
+	}{
+		{1, "2BB183AF5F22588179A53B0A98631FAD1A292118"},
+		{2, "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A"},
+	}
+
+	for _, tt := range tests {
+		record, err := key.DelegationSignerRecord("DSKEY.example.com.", tt.digestType)
+		if err != nil {
+			t.Fatalf("DNSKEY.DelegationSignerRecord(%v) returned error: %v", tt.digestType, err)
+		}
+
+		want := DelegationSignerRecord{Algorithm: "5", Digest: tt.digest, DigestType: strconv.Itoa(int(tt.digestType)), Keytag: "60485"}
+		if *record != want {
+			t.Errorf("DNSKEY.DelegationSignerRecord(%v) returned %+v, want %+v", tt.digestType, *record, want)
+		}
+	}
+
+	// The example of RFC 6605 section 6.2.
+	key, _ = ParseDNSKEY("257 3 14 xKYaNhWdGOfJ+nPrL8/arkwf2EY3MDJ+SErKivBVSum1w/egsXvSADtNJhyem5RCOpgQ6K8X1DRSEkrbYQ+OB+v8/uX45NBwY8rp65F6Glur8I/mlVNgF6W/qTI37m40")
+	record, err := key.DelegationSignerRecord("example.net", 4)
+	if err != nil {
+		t.Fatalf("DNSKEY.DelegationSignerRecord(4) returned error: %v", err)
+	}
+	want := DelegationSignerRecord{Algorithm: "14", Digest: "72D7B62976CE06438E9C0BF319013CF801F09ECC84B8D7E9495F27E305C6A9B0563A9B5F4D288405C3008A946DF983D6", DigestType: "4", Keytag: "10771"}
+	if *record != want {
+		t.Errorf("DNSKEY.DelegationSignerRecord(4) returned %+v, want %+v", *record, want)
+	}
+
+	if _, err := key.DelegationSignerRecord("dskey.example.com", 99); err == nil {
+		t.Errorf("DNSKEY.DelegationSignerRecord(99) expected to return an error")
+	}
+}
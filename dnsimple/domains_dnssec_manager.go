@@ -0,0 +1,349 @@
+package dnsimple
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// defaultDigestType is the digest type of the DS records created by the DnssecManager: SHA-256.
+const defaultDigestType = 2
+
+// ChainOfTrustState represents the state of the DNSSEC chain of trust of a domain,
+// between the DS records published at the registry and the keys of the zone.
+type ChainOfTrustState string
+
+const (
+	// DNSSEC is disabled, and no DS record is published.
+	ChainOfTrustUnsigned = ChainOfTrustState("unsigned")
+
+	// DNSSEC is enabled, but the zone doesn't publish any key yet.
+	ChainOfTrustPending = ChainOfTrustState("pending")
+
+	// The zone is signed, but no DS record is published: resolvers treat it as unsigned.
+	ChainOfTrustInsecure = ChainOfTrustState("insecure")
+
+	// Every key signing key has a DS record, and there is no stale DS record.
+	ChainOfTrustSecure = ChainOfTrustState("secure")
+
+	// At least one key signing key has a DS record, but some are missing
+	// or some DS records don't match any key.
+	ChainOfTrustIncomplete = ChainOfTrustState("incomplete")
+
+	// DS records are published, but none matches a key of the zone:
+	// validating resolvers fail to resolve the domain.
+	ChainOfTrustBroken = ChainOfTrustState("broken")
+)
+
+// DnssecStatus represents the DNSSEC state of a domain.
+type DnssecStatus struct {
+	Domain  string
+	Enabled bool
+
+	// The key signing keys published in the zone.
+	Keys []DNSKEY
+
+	// The DS records published for the domain.
+	Published []DelegationSignerRecord
+
+	// The DS records of the keys that have no published DS record.
+	Missing []DelegationSignerRecord
+
+	// The published DS records that don't match any key.
+	Stale []DelegationSignerRecord
+
+	State ChainOfTrustState
+}
+
+// DnssecSyncResult represents the changes made by a DnssecManager to the DS records of a domain.
+type DnssecSyncResult struct {
+	// The status after the changes.
+	Status *DnssecStatus
+
+	Created []DelegationSignerRecord
+	Deleted []DelegationSignerRecord
+}
+
+// DnssecManager enables DNSSEC on domains and keeps their DS records
+// in sync with the keys of the zone, across key rotations.
+type DnssecManager struct {
+	client *Client
+
+	// Keys returns the DNSKEY records published in the zone of the domain.
+	// Defaults to the DNSKEY records of the DNSimple zone.
+	Keys func(accountID string, zoneName string) ([]DNSKEY, error)
+
+	// The digest type of the DS records created by the manager.
+	// Defaults to 2 (SHA-256).
+	DigestType uint8
+}
+
+// NewDnssecManager returns a DnssecManager.
+func NewDnssecManager(c *Client) *DnssecManager {
+	return &DnssecManager{client: c, DigestType: defaultDigestType}
+}
+
+// Enable enables DNSSEC on the domain, and publishes the DS records of the zone keys.
+// See Sync.
+func (m *DnssecManager) Enable(accountID string, domainName string) (*DnssecSyncResult, error) {
+	if _, err := m.client.Domains.EnableDnssec(accountID, domainName); err != nil {
+		return nil, fmt.Errorf("enabling DNSSEC: %v", err)
+	}
+	return m.Sync(accountID, domainName)
+}
+
+// Status compares the DS records of the domain with the keys of the zone,
+// and reports the state of the chain of trust.
+func (m *DnssecManager) Status(accountID string, domainName string) (*DnssecStatus, error) {
+	dnssecResponse, err := m.client.Domains.GetDnssec(accountID, domainName)
+	if err != nil {
+		return nil, fmt.Errorf("getting DNSSEC status: %v", err)
+	}
+
+	var keys []DNSKEY
+	if dnssecResponse.Data.Enabled {
+		keys, err = m.keySigningKeys(accountID, domainName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return m.status(accountID, domainName, dnssecResponse.Data.Enabled, keys)
+}
+
+// Sync publishes the missing DS records of the zone keys, and deletes the DS records
+// that don't match any key. The stale records are deleted only once all the DS records
+// of the current keys are published, so that the chain of trust is never broken
+// during a key rotation.
+//
+// Sync doesn't change anything when DNSSEC is disabled, or when the zone
+// doesn't publish any key.
+func (m *DnssecManager) Sync(accountID string, domainName string) (*DnssecSyncResult, error) {
+	status, err := m.Status(accountID, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DnssecSyncResult{Status: status}
+	if !status.Enabled || len(status.Keys) == 0 {
+		return result, nil
+	}
+
+	for _, record := range status.Missing {
+		recordResponse, err := m.client.Domains.CreateDelegationSignerRecord(accountID, domainName, record)
+		if err != nil {
+			return result, fmt.Errorf("creating DS record %v: %v", record.Keytag, err)
+		}
+		result.Created = append(result.Created, *recordResponse.Data)
+	}
+
+	if len(status.Stale) == 0 {
+		if len(result.Created) > 0 {
+			result.Status, err = m.status(accountID, domainName, true, status.Keys)
+		}
+		return result, err
+	}
+
+	// Read the records back before deleting anything.
+	status, err = m.status(accountID, domainName, true, status.Keys)
+	if err != nil {
+		return result, err
+	}
+	result.Status = status
+	if len(status.Missing) > 0 {
+		return result, fmt.Errorf("DS record %v is not published, stale DS records are kept", status.Missing[0].Keytag)
+	}
+
+	for _, record := range status.Stale {
+		if _, err := m.client.Domains.DeleteDelegationSignerRecord(accountID, domainName, record.ID); err != nil {
+			return result, fmt.Errorf("deleting DS record %v: %v", record.Keytag, err)
+		}
+		result.Deleted = append(result.Deleted, record)
+	}
+
+	result.Status, err = m.status(accountID, domainName, true, status.Keys)
+	return result, err
+}
+
+// HandleWebhookEvent keeps the DS records in sync with a DNSSEC key rotation.
+//
+// On dnssec.rotation_start the DS record of the new key is published,
+// and on dnssec.rotation_complete the DS records are synced, which removes
+// the record of the retired key. Other events are ignored, and return a nil result.
+//
+// Pass the name and the DS record of a webhook.DNSSECEvent.
+func (m *DnssecManager) HandleWebhookEvent(accountID string, eventName string, record *DelegationSignerRecord) (*DnssecSyncResult, error) {
+	if record == nil || record.DomainID == 0 {
+		return nil, nil
+	}
+	domainID := strconv.FormatInt(record.DomainID, 10)
+
+	switch eventName {
+	case "dnssec.rotation_start":
+		published, err := m.listDelegationSignerRecords(accountID, domainID)
+		if err != nil {
+			return nil, err
+		}
+
+		result := &DnssecSyncResult{}
+		for _, r := range published {
+			if sameDelegationSignerRecord(r, *record) {
+				return result, nil
+			}
+		}
+
+		newRecord := DelegationSignerRecord{Algorithm: record.Algorithm, Digest: record.Digest, DigestType: record.DigestType, Keytag: record.Keytag}
+		if err := ValidateDelegationSignerRecord(newRecord); err != nil {
+			return result, err
+		}
+		recordResponse, err := m.client.Domains.CreateDelegationSignerRecord(accountID, domainID, newRecord)
+		if err != nil {
+			return result, fmt.Errorf("creating DS record %v: %v", record.Keytag, err)
+		}
+		result.Created = append(result.Created, *recordResponse.Data)
+		return result, nil
+
+	case "dnssec.rotation_complete":
+		domainResponse, err := m.client.Domains.GetDomain(accountID, domainID)
+		if err != nil {
+			return nil, fmt.Errorf("getting domain: %v", err)
+		}
+		return m.Sync(accountID, domainResponse.Data.Name)
+	}
+
+	return nil, nil
+}
+
+// status lists the DS records of the domain and compares them with the keys.
+func (m *DnssecManager) status(accountID string, domainName string, enabled bool, keys []DNSKEY) (*DnssecStatus, error) {
+	published, err := m.listDelegationSignerRecords(accountID, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &DnssecStatus{Domain: domainName, Enabled: enabled, Keys: keys, Published: published}
+
+	matched := make([]bool, len(published))
+	for _, key := range keys {
+		found := false
+		for i, record := range published {
+			if delegationSignerRecordMatches(record, key, domainName) {
+				matched[i], found = true, true
+			}
+		}
+		if found {
+			continue
+		}
+
+		record, err := key.DelegationSignerRecord(domainName, m.digestType())
+		if err != nil {
+			return nil, err
+		}
+		status.Missing = append(status.Missing, *record)
+	}
+	for i, record := range published {
+		if !matched[i] {
+			status.Stale = append(status.Stale, record)
+		}
+	}
+
+	switch {
+	case !enabled && len(published) == 0:
+		status.State = ChainOfTrustUnsigned
+	case !enabled:
+		status.State = ChainOfTrustBroken
+	case len(keys) == 0:
+		status.State = ChainOfTrustPending
+	case len(published) == 0:
+		status.State = ChainOfTrustInsecure
+	case len(status.Stale) == len(published):
+		status.State = ChainOfTrustBroken
+	case len(status.Missing) > 0 || len(status.Stale) > 0:
+		status.State = ChainOfTrustIncomplete
+	default:
+		status.State = ChainOfTrustSecure
+	}
+
+	return status, nil
+}
+
+// keySigningKeys returns the key signing keys of the zone,
+// or all the keys when none has the Secure Entry Point flag.
+func (m *DnssecManager) keySigningKeys(accountID string, domainName string) ([]DNSKEY, error) {
+	keysFunc := m.Keys
+	if keysFunc == nil {
+		keysFunc = m.zoneKeys
+	}
+
+	keys, err := keysFunc(accountID, domainName)
+	if err != nil {
+		return nil, fmt.Errorf("getting DNSKEY records: %v", err)
+	}
+
+	var signingKeys []DNSKEY
+	for _, key := range keys {
+		if key.IsKeySigningKey() {
+			signingKeys = append(signingKeys, key)
+		}
+	}
+	if len(signingKeys) == 0 {
+		return keys, nil
+	}
+	return signingKeys, nil
+}
+
+// zoneKeys returns the DNSKEY records of the DNSimple zone.
+func (m *DnssecManager) zoneKeys(accountID string, zoneName string) ([]DNSKEY, error) {
+	records, err := listAllZoneRecords(m.client, accountID, zoneName, &ZoneRecordListOptions{Type: "DNSKEY"})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]DNSKEY, 0, len(records))
+	for _, record := range records {
+		key, err := ParseDNSKEY(record.Content)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	return keys, nil
+}
+
+func (m *DnssecManager) listDelegationSignerRecords(accountID string, domainIdentifier string) ([]DelegationSignerRecord, error) {
+	var records []DelegationSignerRecord
+	err := eachPage(func(options ListOptions) (*Pagination, error) {
+		recordsResponse, err := m.client.Domains.ListDelegationSignerRecords(accountID, domainIdentifier, &options)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, recordsResponse.Data...)
+		return recordsResponse.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing DS records: %v", err)
+	}
+	return records, nil
+}
+
+func (m *DnssecManager) digestType() uint8 {
+	if m.DigestType == 0 {
+		return defaultDigestType
+	}
+	return m.DigestType
+}
+
+// delegationSignerRecordMatches returns true if the DS record references the key.
+// When the digest type is not supported, only the key tag and the algorithm are compared.
+func delegationSignerRecordMatches(record DelegationSignerRecord, key DNSKEY, zoneName string) bool {
+	digestType, err := strconv.ParseUint(record.DigestType, 10, 8)
+	if err != nil {
+		return false
+	}
+
+	expected, err := key.DelegationSignerRecord(zoneName, uint8(digestType))
+	if err != nil {
+		keyTag, err := key.KeyTag()
+		return err == nil && record.Keytag == strconv.Itoa(int(keyTag)) && record.Algorithm == strconv.Itoa(int(key.Algorithm))
+	}
+	return sameDelegationSignerRecord(record, *expected)
+}
@@ -0,0 +1,287 @@
+package dnsimple
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	dnssecOldKey = "257 3 8 AwEAAbK6VRb4qG/yQkZQ3xN0jWqP0xwlOWCUmKzF0c6GhR8p3W1eKq2T"
+	dnssecNewKey = "257 3 8 AwEAAdQ9bQmH5mGd6aYQ0WkNq3Vr9yZ2pCxT8sL1eJ4uF7oR0iK5nB3w"
+	dnssecZSK    = "256 3 8 AwEAAcX2nP8yR4tV1wQ6mZ3kL9jH0fB5sD7gA2eU4iO8qT1rY6vN3xC5"
+)
+
+// dnssecFixtures simulates the DNSSEC state, the DNSKEY records and the DS records of example.com.
+type dnssecFixtures struct {
+	mu       sync.Mutex
+	enabled  bool
+	keys     []string
+	records  []DelegationSignerRecord
+	nextID   int64
+	requests []string
+}
+
+func setupDnssecFixtures(t *testing.T) *dnssecFixtures {
+	f := &dnssecFixtures{nextID: 100}
+
+	mux.HandleFunc("/v2/1010/domains/example.com/dnssec", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		f.requests = append(f.requests, r.Method+" dnssec")
+		if r.Method == "POST" {
+			f.enabled = true
+			httpResponse := httpResponseFixture(t, "/api/enableDnssec/success.http")
+
+			w.WriteHeader(httpResponse.StatusCode)
+			io.Copy(w, httpResponse.Body)
+			return
+		}
+		writeData(w, r, http.StatusOK, Dnssec{Enabled: f.enabled})
+	})
+	mux.HandleFunc("/v2/1010/zones/example.com/records", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if want, got := "DNSKEY", r.URL.Query().Get("type"); want != got {
+			t.Errorf("Request type expected to be `%v`, got `%v`", want, got)
+		}
+
+		var records []ZoneRecord
+		for i, key := range f.keys {
+			records = append(records, ZoneRecord{ID: int64(i + 1), ZoneID: "example.com", Type: "DNSKEY", Content: key})
+		}
+		writeData(w, r, http.StatusOK, records)
+	})
+	dsHandler := func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		switch r.Method {
+		case "GET":
+			writeData(w, r, http.StatusOK, f.records)
+		case "POST":
+			var record DelegationSignerRecord
+			json.NewDecoder(r.Body).Decode(&record)
+			f.requests = append(f.requests, "POST ds "+record.Keytag)
+			record.ID, record.DomainID = f.nextID, 1
+			f.nextID++
+			f.records = append(f.records, record)
+			writeData(w, r, http.StatusCreated, record)
+		case "DELETE":
+			id, _ := strconv.ParseInt(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], 10, 64)
+			kept := f.records[:0]
+			for _, record := range f.records {
+				if record.ID == id {
+					f.requests = append(f.requests, "DELETE ds "+record.Keytag)
+					continue
+				}
+				kept = append(kept, record)
+			}
+			f.records = kept
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+	mux.HandleFunc("/v2/1010/domains/example.com/ds_records", dsHandler)
+	mux.HandleFunc("/v2/1010/domains/example.com/ds_records/", dsHandler)
+	mux.HandleFunc("/v2/1010/domains/1/ds_records", dsHandler)
+	mux.HandleFunc("/v2/1010/domains/1", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, r, http.StatusOK, Domain{ID: 1, Name: "example.com"})
+	})
+
+	return f
+}
+
+// publish adds the DS record of the key, as the registrar would.
+func (f *dnssecFixtures) publish(t *testing.T, content string) DelegationSignerRecord {
+	key, _ := ParseDNSKEY(content)
+	record, err := key.DelegationSignerRecord("example.com", 2)
+	if err != nil {
+		t.Fatalf("DNSKEY.DelegationSignerRecord() returned error: %v", err)
+	}
+
+	record.ID, record.DomainID = f.nextID, 1
+	f.nextID++
+	f.records = append(f.records, *record)
+	return *record
+}
+
+func keyTagOf(content string) string {
+	key, _ := ParseDNSKEY(content)
+	keyTag, _ := key.KeyTag()
+	return fmt.Sprint(keyTag)
+}
+
+func TestDnssecManager_Enable(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupDnssecFixtures(t)
+	// The last key is on the second page of the DNSKEY records.
+	f.keys = []string{dnssecOldKey, dnssecZSK, dnssecNewKey}
+
+	result, err := NewDnssecManager(client).Enable("1010", "example.com")
+	if err != nil {
+		t.Fatalf("DnssecManager.Enable() returned error: %v", err)
+	}
+
+	if want, got := 2, len(result.Created); want != got {
+		t.Fatalf("DnssecManager.Enable() expected to create %v DS records, got %v", want, got)
+	}
+	if want, got := keyTagOf(dnssecOldKey), result.Created[0].Keytag; want != got {
+		t.Errorf("DnssecManager.Enable() created DS record Keytag expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := keyTagOf(dnssecNewKey), result.Created[1].Keytag; want != got {
+		t.Errorf("DnssecManager.Enable() created DS record Keytag expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := ChainOfTrustSecure, result.Status.State; want != got {
+		t.Errorf("DnssecManager.Enable() returned State expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestDnssecManager_Sync_Rotation(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupDnssecFixtures(t)
+	f.enabled = true
+	f.publish(t, dnssecOldKey)
+	f.publish(t, dnssecZSK)
+
+	// The rotation starts: both keys are published in the zone.
+	f.keys = []string{dnssecOldKey, dnssecNewKey}
+	manager := NewDnssecManager(client)
+
+	status, err := manager.Status("1010", "example.com")
+	if err != nil {
+		t.Fatalf("DnssecManager.Status() returned error: %v", err)
+	}
+	if want, got := ChainOfTrustIncomplete, status.State; want != got {
+		t.Errorf("DnssecManager.Status() returned State expected to be `%v`, got `%v`", want, got)
+	}
+	if len(status.Missing) != 1 || len(status.Stale) != 1 {
+		t.Errorf("DnssecManager.Status() expected 1 missing and 1 stale DS record, got %+v", status)
+	}
+
+	f.requests = nil
+	result, err := manager.Sync("1010", "example.com")
+	if err != nil {
+		t.Fatalf("DnssecManager.Sync() returned error: %v", err)
+	}
+
+	// The new record is published before the stale one is deleted, and the old key keeps its record.
+	want := []string{"GET dnssec", "POST ds " + keyTagOf(dnssecNewKey), "DELETE ds " + keyTagOf(dnssecZSK)}
+	if fmt.Sprint(f.requests) != fmt.Sprint(want) {
+		t.Errorf("DnssecManager.Sync() requests expected to be %v, got %v", want, f.requests)
+	}
+	if want, got := ChainOfTrustSecure, result.Status.State; want != got {
+		t.Errorf("DnssecManager.Sync() returned State expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := 2, len(result.Status.Published); want != got {
+		t.Errorf("DnssecManager.Sync() expected %v published DS records, got %v", want, got)
+	}
+
+	// The rotation completes: the old key is retired.
+	f.keys = []string{dnssecNewKey}
+	f.requests = nil
+	result, err = manager.HandleWebhookEvent("1010", "dnssec.rotation_complete", &DelegationSignerRecord{DomainID: 1})
+	if err != nil {
+		t.Fatalf("DnssecManager.HandleWebhookEvent() returned error: %v", err)
+	}
+	if want, got := 1, len(result.Deleted); want != got {
+		t.Fatalf("DnssecManager.HandleWebhookEvent() expected to delete %v DS records, got %v", want, got)
+	}
+	if want, got := keyTagOf(dnssecOldKey), result.Deleted[0].Keytag; want != got {
+		t.Errorf("DnssecManager.HandleWebhookEvent() deleted Keytag expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestDnssecManager_Sync_NoKeys(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupDnssecFixtures(t)
+	f.enabled = true
+	f.publish(t, dnssecOldKey)
+
+	result, err := NewDnssecManager(client).Sync("1010", "example.com")
+	if err != nil {
+		t.Fatalf("DnssecManager.Sync() returned error: %v", err)
+	}
+
+	if want, got := ChainOfTrustPending, result.Status.State; want != got {
+		t.Errorf("DnssecManager.Sync() returned State expected to be `%v`, got `%v`", want, got)
+	}
+	if len(result.Deleted) != 0 || len(f.records) != 1 {
+		t.Errorf("DnssecManager.Sync() expected not to delete DS records without keys")
+	}
+}
+
+func TestDnssecManager_Status(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupDnssecFixtures(t)
+	manager := NewDnssecManager(client)
+
+	states := []struct {
+		setup func()
+		want  ChainOfTrustState
+	}{
+		{func() {}, ChainOfTrustUnsigned},
+		{func() { f.enabled = true; f.keys = []string{dnssecOldKey} }, ChainOfTrustInsecure},
+		{func() { f.publish(t, dnssecNewKey) }, ChainOfTrustBroken},
+		{func() { f.publish(t, dnssecOldKey) }, ChainOfTrustIncomplete},
+		{func() { f.records = f.records[1:] }, ChainOfTrustSecure},
+		{func() { f.enabled = false }, ChainOfTrustBroken},
+	}
+
+	for i, tt := range states {
+		tt.setup()
+		status, err := manager.Status("1010", "example.com")
+		if err != nil {
+			t.Fatalf("DnssecManager.Status() returned error: %v", err)
+		}
+		if want, got := tt.want, status.State; want != got {
+			t.Errorf("DnssecManager.Status() step %v returned State expected to be `%v`, got `%v`", i, want, got)
+		}
+	}
+}
+
+func TestDnssecManager_HandleWebhookEvent_RotationStart(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupDnssecFixtures(t)
+	f.enabled = true
+	old := f.publish(t, dnssecOldKey)
+
+	key, _ := ParseDNSKEY(dnssecNewKey)
+	record, _ := key.DelegationSignerRecord("example.com", 2)
+	record.DomainID = 1
+
+	manager := NewDnssecManager(client)
+	for i := 0; i < 2; i++ {
+		if _, err := manager.HandleWebhookEvent("1010", "dnssec.rotation_start", record); err != nil {
+			t.Fatalf("DnssecManager.HandleWebhookEvent() returned error: %v", err)
+		}
+	}
+
+	if want, got := 2, len(f.records); want != got {
+		t.Fatalf("DnssecManager.HandleWebhookEvent() expected %v DS records, got %v", want, got)
+	}
+	if f.records[0].ID != old.ID {
+		t.Errorf("DnssecManager.HandleWebhookEvent() expected to keep the DS record of the old key")
+	}
+
+	result, err := manager.HandleWebhookEvent("1010", "domain.create", record)
+	if err != nil || result != nil {
+		t.Errorf("DnssecManager.HandleWebhookEvent() expected to ignore other events, got %v, %v", result, err)
+	}
+}
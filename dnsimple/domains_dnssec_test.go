@@ -25,14 +25,19 @@ func TestDomainsService_EnableDnssec(t *testing.T) {
 		testHeaders(t, r)
 
 		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
 	})
 
 	accountID := "1010"
 
-	_, err := client.Domains.EnableDnssec(accountID, "example.com")
+	dnssecResponse, err := client.Domains.EnableDnssec(accountID, "example.com")
 	if err != nil {
 		t.Fatalf("Domains.EnableDnssec() returned error: %v", err)
 	}
+
+	if want, got := true, dnssecResponse.Data.Enabled; want != got {
+		t.Errorf("Domains.EnableDnssec() returned Enabled expected to be `%v`, got `%v`", want, got)
+	}
 }
 
 func TestDomainsService_DisableDnssec(t *testing.T) {
@@ -0,0 +1,346 @@
+package dnsimple
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// emailForwardCatchAll is the local part of the forward that receives the emails
+// sent to the addresses of the domain without a forward.
+const emailForwardCatchAll = ".*"
+
+var (
+	emailLocalPartRegexp = regexp.MustCompile(`^[a-z0-9!#$%&'*+/=?^_{|}~-]+(\.[a-z0-9!#$%&'*+/=?^_{|}~-]+)*$`)
+	emailDomainRegexp    = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`)
+)
+
+// EmailForwardError represents an email forward that can't be synced.
+type EmailForwardError struct {
+	From    string
+	To      string
+	Message string
+}
+
+// Error implements the error interface.
+func (e EmailForwardError) Error() string {
+	return fmt.Sprintf("email forward %v -> %v %v", e.From, e.To, e.Message)
+}
+
+// EmailForwardsError represents the list of invalid email forwards
+// returned by ValidateEmailForwards and PlanEmailForwards.
+type EmailForwardsError struct {
+	Errors []EmailForwardError
+}
+
+// Error implements the error interface.
+func (e *EmailForwardsError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "invalid email forwards: " + strings.Join(messages, "; ")
+}
+
+// EmailForwardChangeType identifies the change of an email forward.
+type EmailForwardChangeType string
+
+const (
+	// EmailForwardCreate is the creation of a forward missing from the domain.
+	EmailForwardCreate = EmailForwardChangeType("create")
+
+	// EmailForwardDelete is the deletion of a forward that is not in the desired forwards.
+	EmailForwardDelete = EmailForwardChangeType("delete")
+)
+
+// EmailForwardChange represents the creation or the deletion of an email forward.
+type EmailForwardChange struct {
+	Type    EmailForwardChangeType
+	Forward EmailForward
+
+	// The error returned while applying the change, if any.
+	Err error
+}
+
+// EmailForwardPlan represents the changes that sync the email forwards of a domain.
+// Email forwards can't be updated: a forward to a new destination is created,
+// and the forward to the previous destination is deleted.
+type EmailForwardPlan struct {
+	Domain string
+
+	// The creations, followed by the deletions, so that the emails
+	// are always forwarded while the plan is applied.
+	Changes []EmailForwardChange
+
+	Unchanged []EmailForward
+}
+
+// String returns a human-readable description of the changes.
+func (p *EmailForwardPlan) String() string {
+	if len(p.Changes) == 0 {
+		return fmt.Sprintf("%v: no changes", p.Domain)
+	}
+
+	lines := make([]string, len(p.Changes))
+	for i, change := range p.Changes {
+		sign := "+"
+		if change.Type == EmailForwardDelete {
+			sign = "-"
+		}
+		lines[i] = fmt.Sprintf("%v %v -> %v", sign, change.Forward.From, change.Forward.To)
+	}
+	return fmt.Sprintf("%v:\n%v", p.Domain, strings.Join(lines, "\n"))
+}
+
+// ValidateEmailForwards checks the desired email forwards of a domain, keyed by alias.
+//
+// An alias is either a local part, or an address of the domain. The alias ".*"
+// forwards the emails sent to the addresses without a forward. The destinations
+// must be valid addresses. It returns an *EmailForwardsError listing all
+// the problems, or nil if the forwards are valid.
+func ValidateEmailForwards(domainName string, forwards map[string]string) error {
+	_, err := normalizeEmailForwards(domainName, forwards)
+	return err
+}
+
+// PlanEmailForwards computes the changes that make the email forwards of the domain
+// match the desired forwards, keyed by alias. The forwards that are not desired
+// are deleted.
+//
+// The desired forwards are validated first, see ValidateEmailForwards. The forwards
+// are then followed through the forwards of the other domains of the account,
+// and forwarding loops are reported as errors. In both cases, the returned error
+// is an *EmailForwardsError.
+func PlanEmailForwards(c *Client, accountID string, domainName string, forwards map[string]string) (*EmailForwardPlan, error) {
+	domainName = strings.ToLower(domainName)
+
+	desired, err := normalizeEmailForwards(domainName, forwards)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := listAllEmailForwards(c, accountID, domainName)
+	if err != nil {
+		return nil, fmt.Errorf("listing email forwards for %v: %v", domainName, err)
+	}
+
+	if err := checkEmailForwardLoops(c, accountID, domainName, desired); err != nil {
+		return nil, err
+	}
+
+	plan := &EmailForwardPlan{Domain: domainName}
+	existing := map[string]bool{}
+	var deletes []EmailForwardChange
+	for _, forward := range current {
+		key := strings.ToLower(forward.From) + " " + strings.ToLower(forward.To)
+		if desired[strings.ToLower(forward.From)] == strings.ToLower(forward.To) && !existing[key] {
+			existing[key] = true
+			plan.Unchanged = append(plan.Unchanged, forward)
+			continue
+		}
+		deletes = append(deletes, EmailForwardChange{Type: EmailForwardDelete, Forward: forward})
+	}
+
+	for _, from := range sortedKeys(desired) {
+		if !existing[from+" "+desired[from]] {
+			plan.Changes = append(plan.Changes, EmailForwardChange{Type: EmailForwardCreate, Forward: EmailForward{From: from, To: desired[from]}})
+		}
+	}
+	plan.Changes = append(plan.Changes, deletes...)
+
+	return plan, nil
+}
+
+// Apply applies the changes of the plan, one at a time.
+//
+// The changes are returned with the error of each change, if any. The returned
+// error is the first error encountered, or the context error if the execution
+// was cancelled, in which case the remaining changes are not applied.
+// A deletion is not applied when a creation failed, so that no forward is lost.
+func (p *EmailForwardPlan) Apply(ctx context.Context, c *Client, accountID string) ([]EmailForwardChange, error) {
+	changes := make([]EmailForwardChange, len(p.Changes))
+	copy(changes, p.Changes)
+
+	var firstErr error
+	createFailed := false
+	for i := range changes {
+		change := &changes[i]
+
+		if err := ctx.Err(); err != nil {
+			change.Err = err
+			firstErr = err
+			continue
+		}
+		if change.Type == EmailForwardDelete && createFailed {
+			change.Err = fmt.Errorf("skipped after a failed creation")
+			continue
+		}
+
+		switch change.Type {
+		case EmailForwardCreate:
+			forwardResponse, err := c.Domains.CreateEmailForward(accountID, p.Domain, EmailForward{From: change.Forward.From, To: change.Forward.To})
+			if err != nil {
+				change.Err, createFailed = err, true
+				break
+			}
+			change.Forward = *forwardResponse.Data
+		case EmailForwardDelete:
+			_, change.Err = c.Domains.DeleteEmailForward(accountID, p.Domain, change.Forward.ID)
+		}
+
+		if change.Err != nil && firstErr == nil {
+			firstErr = change.Err
+		}
+	}
+
+	return changes, firstErr
+}
+
+// normalizeEmailForwards returns the forwards keyed by the address of the alias, in lowercase.
+func normalizeEmailForwards(domainName string, forwards map[string]string) (map[string]string, error) {
+	domainName = strings.ToLower(domainName)
+
+	normalized := map[string]string{}
+	var errors []EmailForwardError
+	for _, alias := range sortedKeys(forwards) {
+		from, to := strings.ToLower(strings.TrimSpace(alias)), strings.ToLower(strings.TrimSpace(forwards[alias]))
+		if !strings.Contains(from, "@") {
+			from += "@" + domainName
+		}
+
+		if message := validateEmailForward(domainName, from, to); message != "" {
+			errors = append(errors, EmailForwardError{From: alias, To: forwards[alias], Message: message})
+			continue
+		}
+		if _, ok := normalized[from]; ok {
+			errors = append(errors, EmailForwardError{From: alias, To: forwards[alias], Message: "is a duplicate alias"})
+			continue
+		}
+		normalized[from] = to
+	}
+
+	if len(errors) > 0 {
+		return nil, &EmailForwardsError{Errors: errors}
+	}
+	return normalized, nil
+}
+
+func validateEmailForward(domainName string, from string, to string) string {
+	fromLocal, fromDomain := splitEmailAddress(from)
+	if fromDomain != domainName {
+		return fmt.Sprintf("has an alias outside of %v", domainName)
+	}
+	if fromLocal != emailForwardCatchAll && !emailLocalPartRegexp.MatchString(fromLocal) {
+		return "has an invalid alias"
+	}
+
+	toLocal, toDomain := splitEmailAddress(to)
+	if !emailLocalPartRegexp.MatchString(toLocal) || !emailDomainRegexp.MatchString(toDomain) {
+		return "has an invalid destination"
+	}
+	if to == from {
+		return "forwards to itself"
+	}
+	return ""
+}
+
+// splitEmailAddress returns the local part and the domain of an address.
+func splitEmailAddress(address string) (string, string) {
+	i := strings.LastIndex(address, "@")
+	if i < 0 {
+		return address, ""
+	}
+	return address[:i], address[i+1:]
+}
+
+// checkEmailForwardLoops follows the desired forwards through the forwards
+// of the other domains of the account, and reports the forwarding loops.
+func checkEmailForwardLoops(c *Client, accountID string, domainName string, desired map[string]string) error {
+	domains, err := listAllDomains(c, accountID)
+	if err != nil {
+		return fmt.Errorf("listing domains: %v", err)
+	}
+	accountDomains := map[string]bool{}
+	for _, domain := range domains {
+		accountDomains[strings.ToLower(domain.Name)] = true
+	}
+
+	// The forwards of each domain, keyed by alias, loaded when a destination is in the domain.
+	forwardsByDomain := map[string]map[string]string{domainName: desired}
+	destination := func(address string) (string, error) {
+		local, domain := splitEmailAddress(address)
+		if !accountDomains[domain] && domain != domainName {
+			return "", nil
+		}
+
+		forwards, ok := forwardsByDomain[domain]
+		if !ok {
+			current, err := listAllEmailForwards(c, accountID, domain)
+			if err != nil {
+				return "", fmt.Errorf("listing email forwards for %v: %v", domain, err)
+			}
+			forwards = map[string]string{}
+			for _, forward := range current {
+				forwards[strings.ToLower(forward.From)] = strings.ToLower(forward.To)
+			}
+			forwardsByDomain[domain] = forwards
+		}
+
+		if to, ok := forwards[address]; ok {
+			return to, nil
+		}
+		if local != emailForwardCatchAll {
+			return forwards[emailForwardCatchAll+"@"+domain], nil
+		}
+		return "", nil
+	}
+
+	var errors []EmailForwardError
+	for _, from := range sortedKeys(desired) {
+		chain := []string{from}
+		visited := map[string]bool{from: true}
+		for address := desired[from]; address != ""; {
+			chain = append(chain, address)
+			if visited[address] {
+				errors = append(errors, EmailForwardError{From: from, To: desired[from], Message: "creates a forwarding loop: " + strings.Join(chain, " -> ")})
+				break
+			}
+			visited[address] = true
+
+			next, err := destination(address)
+			if err != nil {
+				return err
+			}
+			address = next
+		}
+	}
+
+	if len(errors) > 0 {
+		return &EmailForwardsError{Errors: errors}
+	}
+	return nil
+}
+
+func listAllEmailForwards(c *Client, accountID string, domainName string) ([]EmailForward, error) {
+	var forwards []EmailForward
+	err := eachPage(func(options ListOptions) (*Pagination, error) {
+		forwardsResponse, err := c.Domains.ListEmailForwards(accountID, domainName, &options)
+		if err != nil {
+			return nil, err
+		}
+		forwards = append(forwards, forwardsResponse.Data...)
+		return forwardsResponse.Pagination, nil
+	})
+	return forwards, err
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,256 @@
+package dnsimple
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// emailForwardFixtures simulates the email forwards of example.com and example.org.
+type emailForwardFixtures struct {
+	mu       sync.Mutex
+	nextID   int64
+	forwards map[string][]EmailForward
+
+	// The addresses for which the creation of a forward fails.
+	failures map[string]bool
+
+	requests []string
+}
+
+func setupEmailForwardFixtures(t *testing.T) *emailForwardFixtures {
+	f := &emailForwardFixtures{nextID: 100, forwards: map[string][]EmailForward{}, failures: map[string]bool{}}
+
+	mux.HandleFunc("/v2/1010/domains", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, r, http.StatusOK, []Domain{{ID: 1, Name: "example.com"}, {ID: 2, Name: "example.org"}})
+	})
+
+	mux.HandleFunc("/v2/1010/domains/", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v2/1010/domains/"), "/")
+		domainName := parts[0]
+
+		switch {
+		case r.Method == "GET" && len(parts) == 2:
+			f.requests = append(f.requests, "LIST "+domainName)
+			forwards := f.forwards[domainName]
+			if forwards == nil {
+				forwards = []EmailForward{}
+			}
+			writeData(w, r, http.StatusOK, forwards)
+		case r.Method == "POST" && len(parts) == 2:
+			var forward EmailForward
+			json.NewDecoder(r.Body).Decode(&forward)
+			f.requests = append(f.requests, "CREATE "+forward.From+" "+forward.To)
+			if f.failures[forward.From] {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"message":"Validation failed"}`))
+				return
+			}
+			forward.ID = f.nextID
+			f.nextID++
+			f.forwards[domainName] = append(f.forwards[domainName], forward)
+			writeData(w, r, http.StatusCreated, forward)
+		case r.Method == "DELETE" && len(parts) == 3:
+			f.requests = append(f.requests, "DELETE "+parts[2])
+			id, _ := strconv.ParseInt(parts[2], 10, 64)
+			kept := []EmailForward{}
+			for _, forward := range f.forwards[domainName] {
+				if forward.ID != id {
+					kept = append(kept, forward)
+				}
+			}
+			f.forwards[domainName] = kept
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+		}
+	})
+
+	return f
+}
+
+func TestValidateEmailForwards(t *testing.T) {
+	err := ValidateEmailForwards("example.com", map[string]string{
+		"john":              "john@example.net",
+		".*":                "catch-all@example.net",
+		"jane@example.com":  "Jane.Smith@Example.NET",
+		"bad alias":         "bad@example.net",
+		"other@example.org": "other@example.net",
+		"nobody":            "nobody",
+		"loop":              "loop@example.com",
+		"JOHN@example.com":  "john@example.org",
+	})
+
+	forwardsErr, ok := err.(*EmailForwardsError)
+	if !ok {
+		t.Fatalf("ValidateEmailForwards() expected to return an *EmailForwardsError, got `%v`", err)
+	}
+
+	var got []string
+	for _, err := range forwardsErr.Errors {
+		got = append(got, err.From+": "+err.Message)
+	}
+	want := []string{
+		"bad alias: has an invalid alias",
+		"john: is a duplicate alias",
+		"loop: forwards to itself",
+		"nobody: has an invalid destination",
+		"other@example.org: has an alias outside of example.com",
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("ValidateEmailForwards() errors expected to be `%v`, got `%v`", want, got)
+	}
+
+	if err := ValidateEmailForwards("example.com", map[string]string{"john": "john@example.net", ".*": "all@example.net"}); err != nil {
+		t.Errorf("ValidateEmailForwards() returned error: %v", err)
+	}
+}
+
+func TestPlanEmailForwards(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupEmailForwardFixtures(t)
+	f.forwards["example.com"] = []EmailForward{
+		{ID: 1, From: "john@example.com", To: "john@old.example.net"},
+		{ID: 2, From: "jane@example.com", To: "jane@example.net"},
+		{ID: 3, From: "bob@example.com", To: "bob@example.net"},
+	}
+
+	plan, err := PlanEmailForwards(client, "1010", "example.com", map[string]string{
+		"john": "john@new.example.net",
+		"Jane": "jane@example.net",
+		".*":   "all@example.net",
+	})
+	if err != nil {
+		t.Fatalf("PlanEmailForwards() returned error: %v", err)
+	}
+
+	want := []EmailForwardChange{
+		{Type: EmailForwardCreate, Forward: EmailForward{From: ".*@example.com", To: "all@example.net"}},
+		{Type: EmailForwardCreate, Forward: EmailForward{From: "john@example.com", To: "john@new.example.net"}},
+		{Type: EmailForwardDelete, Forward: EmailForward{ID: 1, From: "john@example.com", To: "john@old.example.net"}},
+		{Type: EmailForwardDelete, Forward: EmailForward{ID: 3, From: "bob@example.com", To: "bob@example.net"}},
+	}
+	if !reflect.DeepEqual(want, plan.Changes) {
+		t.Errorf("PlanEmailForwards() returned Changes expected to be `%+v`, got `%+v`", want, plan.Changes)
+	}
+	if want, got := 1, len(plan.Unchanged); want != got {
+		t.Errorf("PlanEmailForwards() returned %v unchanged forwards, expected %v", got, want)
+	}
+
+	wantString := "example.com:\n+ .*@example.com -> all@example.net\n+ john@example.com -> john@new.example.net\n- john@example.com -> john@old.example.net\n- bob@example.com -> bob@example.net"
+	if got := plan.String(); wantString != got {
+		t.Errorf("EmailForwardPlan.String() expected to be `%v`, got `%v`", wantString, got)
+	}
+
+	if _, err := PlanEmailForwards(client, "1010", "example.com", map[string]string{"john": "john"}); err == nil {
+		t.Errorf("PlanEmailForwards() expected to validate the forwards")
+	}
+}
+
+func TestPlanEmailForwards_Loops(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupEmailForwardFixtures(t)
+	f.forwards["example.org"] = []EmailForward{
+		{ID: 1, From: "team@example.org", To: "sales@example.com"},
+		{ID: 2, From: ".*@example.org", To: "info@example.com"},
+	}
+
+	_, err := PlanEmailForwards(client, "1010", "example.com", map[string]string{
+		"sales":   "team@example.org",
+		"info":    "anyone@example.org",
+		"support": "support@example.net",
+		"help":    "support@example.com",
+	})
+
+	forwardsErr, ok := err.(*EmailForwardsError)
+	if !ok {
+		t.Fatalf("PlanEmailForwards() expected to return an *EmailForwardsError, got `%v`", err)
+	}
+
+	var got []string
+	for _, err := range forwardsErr.Errors {
+		got = append(got, err.Message)
+	}
+	want := []string{
+		"creates a forwarding loop: info@example.com -> anyone@example.org -> info@example.com",
+		"creates a forwarding loop: sales@example.com -> team@example.org -> sales@example.com",
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("PlanEmailForwards() errors expected to be `%v`, got `%v`", want, got)
+	}
+
+	// The forwards of example.org are listed once.
+	if want, got := 1, strings.Count(strings.Join(f.requests, "\n"), "LIST example.org"); want != got {
+		t.Errorf("PlanEmailForwards() listed the forwards of example.org %v times, expected %v", got, want)
+	}
+}
+
+func TestEmailForwardPlan_Apply(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupEmailForwardFixtures(t)
+	f.forwards["example.com"] = []EmailForward{
+		{ID: 1, From: "john@example.com", To: "john@old.example.net"},
+		{ID: 2, From: "bob@example.com", To: "bob@example.net"},
+	}
+
+	plan, err := PlanEmailForwards(client, "1010", "example.com", map[string]string{"john": "john@new.example.net"})
+	if err != nil {
+		t.Fatalf("PlanEmailForwards() returned error: %v", err)
+	}
+
+	changes, err := plan.Apply(context.Background(), client, "1010")
+	if err != nil {
+		t.Fatalf("EmailForwardPlan.Apply() returned error: %v", err)
+	}
+	if want, got := int64(100), changes[0].Forward.ID; want != got {
+		t.Errorf("EmailForwardPlan.Apply() returned the created forward with ID `%v`, expected `%v`", got, want)
+	}
+
+	want := []EmailForward{{ID: 100, From: "john@example.com", To: "john@new.example.net"}}
+	if got := f.forwards["example.com"]; !reflect.DeepEqual(want, got) {
+		t.Errorf("EmailForwardPlan.Apply() left forwards `%+v`, expected `%+v`", got, want)
+	}
+}
+
+func TestEmailForwardPlan_Apply_CreateFailure(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupEmailForwardFixtures(t)
+	f.forwards["example.com"] = []EmailForward{{ID: 1, From: "john@example.com", To: "john@old.example.net"}}
+	f.failures["john@example.com"] = true
+
+	plan, err := PlanEmailForwards(client, "1010", "example.com", map[string]string{"john": "john@new.example.net"})
+	if err != nil {
+		t.Fatalf("PlanEmailForwards() returned error: %v", err)
+	}
+
+	changes, err := plan.Apply(context.Background(), client, "1010")
+	if err == nil {
+		t.Fatalf("EmailForwardPlan.Apply() expected to return an error")
+	}
+	if changes[0].Err == nil || changes[1].Err == nil {
+		t.Errorf("EmailForwardPlan.Apply() expected both changes to report an error, got `%+v`", changes)
+	}
+
+	if want, got := 1, len(f.forwards["example.com"]); want != got {
+		t.Errorf("EmailForwardPlan.Apply() expected to keep the current forward")
+	}
+	if strings.Contains(strings.Join(f.requests, "\n"), "DELETE") {
+		t.Errorf("EmailForwardPlan.Apply() expected not to delete forwards after a failed creation")
+	}
+}
@@ -0,0 +1,263 @@
+package dnsimple
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// defaultExpirationWindow is the number of days before the expiration
+// an item is reported as expiring when no window is specified.
+const defaultExpirationWindow = 30
+
+// ExpirationStatus classifies an item by its expiration date.
+type ExpirationStatus string
+
+const (
+	// ExpirationExpired is the status of an item whose expiration date is in the past.
+	ExpirationExpired = ExpirationStatus("expired")
+
+	// ExpirationExpiring is the status of an item that expires within the report window.
+	ExpirationExpiring = ExpirationStatus("expiring")
+
+	// ExpirationValid is the status of an item that expires after the report window.
+	ExpirationValid = ExpirationStatus("valid")
+)
+
+// ExpirationItem represents a domain, WHOIS privacy or certificate with an expiration date.
+type ExpirationItem struct {
+	// The kind of item: domain, whois_privacy or certificate.
+	Kind string `json:"kind"`
+
+	// The name of the domain, or the common name of the certificate.
+	Name string `json:"name"`
+
+	// The name of the domain the item belongs to.
+	Domain string `json:"domain"`
+
+	ExpiresOn time.Time        `json:"expires_on"`
+	DaysLeft  int              `json:"days_left"`
+	Status    ExpirationStatus `json:"status"`
+	AutoRenew bool             `json:"auto_renew"`
+}
+
+// ExpirationReport represents the expiration state of the items in an account.
+type ExpirationReport struct {
+	AccountID   string    `json:"account_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// The number of days before the expiration an item is reported as expiring.
+	Window int `json:"window"`
+
+	// The items, sorted by expiration date.
+	Items []ExpirationItem `json:"items"`
+}
+
+// ExpirationReportOptions specifies the optional parameters you can provide
+// to customize CheckExpirations.
+type ExpirationReportOptions struct {
+	// The number of days before the expiration an item is reported as expiring.
+	// Defaults to 30.
+	Window int
+
+	// The time the report is computed at. Defaults to the current time.
+	Now time.Time
+}
+
+// CheckExpirations scans the domains, WHOIS privacy and certificates of an account
+// and classifies them according to their expiration date.
+//
+// Every domain with an expiration date is considered, including the domains
+// that already expired. Hosted domains are skipped, as they don't expire.
+// Only issued certificates are considered.
+func CheckExpirations(c *Client, accountID string, options *ExpirationReportOptions) (*ExpirationReport, error) {
+	report := &ExpirationReport{AccountID: accountID, Window: defaultExpirationWindow, GeneratedAt: time.Now()}
+	if options != nil {
+		if options.Window > 0 {
+			report.Window = options.Window
+		}
+		if !options.Now.IsZero() {
+			report.GeneratedAt = options.Now
+		}
+	}
+
+	domains, err := listAllDomains(c, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("listing domains: %v", err)
+	}
+
+	for _, domain := range domains {
+		if domain.State != "hosted" {
+			expiresOn, err := domain.ExpiresOnTime()
+			if err != nil {
+				return nil, fmt.Errorf("domain %v: %v", domain.Name, err)
+			}
+			report.add("domain", domain.Name, domain.Name, expiresOn, domain.AutoRenew)
+		}
+
+		if domain.State == "registered" && domain.PrivateWhois {
+			privacyResponse, err := c.Registrar.GetWhoisPrivacy(accountID, domain.Name)
+			if err != nil {
+				return nil, fmt.Errorf("getting WHOIS privacy for %v: %v", domain.Name, err)
+			}
+			if privacy := privacyResponse.Data; privacy != nil && privacy.Enabled {
+				expiresOn, err := privacy.ExpiresOnTime()
+				if err != nil {
+					return nil, fmt.Errorf("WHOIS privacy for %v: %v", domain.Name, err)
+				}
+				report.add("whois_privacy", domain.Name, domain.Name, expiresOn, domain.AutoRenew)
+			}
+		}
+
+		err := eachPage(func(options ListOptions) (*Pagination, error) {
+			certificatesResponse, err := c.Certificates.ListCertificates(accountID, domain.Name, &options)
+			if err != nil {
+				return nil, err
+			}
+			for _, certificate := range certificatesResponse.Data {
+				if certificate.State != "issued" {
+					continue
+				}
+				expiresOn, err := certificate.ExpiresOnTime()
+				if err != nil {
+					return nil, fmt.Errorf("certificate %v: %v", certificate.CommonName, err)
+				}
+				report.add("certificate", certificate.CommonName, domain.Name, expiresOn, certificate.AutoRenew)
+			}
+			return certificatesResponse.Pagination, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing certificates for %v: %v", domain.Name, err)
+		}
+	}
+
+	sort.Stable(expirationItemsByDate(report.Items))
+
+	return report, nil
+}
+
+type expirationItemsByDate []ExpirationItem
+
+func (s expirationItemsByDate) Len() int           { return len(s) }
+func (s expirationItemsByDate) Less(i, j int) bool { return s[i].ExpiresOn.Before(s[j].ExpiresOn) }
+func (s expirationItemsByDate) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// add classifies an item and adds it to the report.
+// Items without an expiration date are ignored.
+func (r *ExpirationReport) add(kind, name, domain string, expiration time.Time, autoRenew bool) {
+	if expiration.IsZero() {
+		return
+	}
+
+	item := ExpirationItem{Kind: kind, Name: name, Domain: domain, ExpiresOn: expiration, AutoRenew: autoRenew}
+	item.DaysLeft = int(expiration.Sub(r.GeneratedAt).Hours() / 24)
+
+	switch {
+	case !expiration.After(r.GeneratedAt):
+		item.Status = ExpirationExpired
+	case expiration.Before(r.GeneratedAt.AddDate(0, 0, r.Window)):
+		item.Status = ExpirationExpiring
+	default:
+		item.Status = ExpirationValid
+	}
+
+	r.Items = append(r.Items, item)
+}
+
+// Filter returns the items with the given status.
+func (r *ExpirationReport) Filter(status ExpirationStatus) []ExpirationItem {
+	var items []ExpirationItem
+	for _, item := range r.Items {
+		if item.Status == status {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// AutoRenewDisabled returns the items that won't be renewed automatically.
+func (r *ExpirationReport) AutoRenewDisabled() []ExpirationItem {
+	var items []ExpirationItem
+	for _, item := range r.Items {
+		if !item.AutoRenew {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// WriteText writes the report as a human-readable table.
+func (r *ExpirationReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "STATUS\tKIND\tNAME\tEXPIRES ON\tDAYS LEFT\tAUTO RENEW\n")
+	for _, item := range r.Items {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\n",
+			item.Status, item.Kind, item.Name, item.ExpiresOn.Format("2006-01-02"), item.DaysLeft, item.AutoRenew)
+	}
+
+	return tw.Flush()
+}
+
+// WriteJSON writes the report as JSON.
+func (r *ExpirationReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WritePrometheus writes the report in the Prometheus text exposition format.
+func (r *ExpirationReport) WritePrometheus(w io.Writer) error {
+	metrics := []struct {
+		name  string
+		help  string
+		value func(item ExpirationItem) float64
+	}{
+		{
+			"dnsimple_expiration_timestamp_seconds",
+			"Expiration date of the item, in seconds since the epoch.",
+			func(item ExpirationItem) float64 { return float64(item.ExpiresOn.Unix()) },
+		},
+		{
+			"dnsimple_expiration_days_left",
+			"Number of days left before the item expires.",
+			func(item ExpirationItem) float64 { return float64(item.DaysLeft) },
+		},
+		{
+			"dnsimple_expiration_auto_renew",
+			"Whether the item is renewed automatically (1) or not (0).",
+			func(item ExpirationItem) float64 {
+				if item.AutoRenew {
+					return 1
+				}
+				return 0
+			},
+		},
+	}
+
+	for _, metric := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %v %v\n# TYPE %v gauge\n", metric.name, metric.help, metric.name); err != nil {
+			return err
+		}
+		for _, item := range r.Items {
+			labels := fmt.Sprintf(`account="%v",kind="%v",name="%v",domain="%v",status="%v"`,
+				escapePrometheusLabel(r.AccountID), item.Kind, escapePrometheusLabel(item.Name), escapePrometheusLabel(item.Domain), item.Status)
+			if _, err := fmt.Fprintf(w, "%v{%v} %v\n", metric.name, labels, strconv.FormatFloat(metric.value(item), 'f', -1, 64)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+var prometheusLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapePrometheusLabel(value string) string {
+	return prometheusLabelReplacer.Replace(value)
+}
@@ -0,0 +1,133 @@
+package dnsimple
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupExpirationFixtures(t *testing.T) {
+	serveFixture(t, "/v2/1010/domains", "/api/listDomains/success.http")
+	serveFixture(t, "/v2/1010/domains/example-alpha.com/certificates", "/api/listCertificates/success.http")
+	serveFixture(t, "/v2/1010/domains/example-beta.com/certificates", "/api/listCertificates/success.http")
+}
+
+func TestCheckExpirations(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	setupExpirationFixtures(t)
+
+	now := time.Date(2015, 11, 20, 0, 0, 0, 0, time.UTC)
+	report, err := CheckExpirations(client, "1010", &ExpirationReportOptions{Now: now})
+	if err != nil {
+		t.Fatalf("CheckExpirations() returned error: %v", err)
+	}
+
+	// 1 registered domain, and 1 issued certificate with an expiration date for each domain.
+	if want, got := 3, len(report.Items); want != got {
+		t.Fatalf("CheckExpirations() expected to return %v items, got %v", want, got)
+	}
+
+	domain := report.Items[0]
+	if want, got := "example-beta.com", domain.Name; want != got {
+		t.Errorf("CheckExpirations() first item Name expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := ExpirationExpiring, domain.Status; want != got {
+		t.Errorf("CheckExpirations() domain Status expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := 16, domain.DaysLeft; want != got {
+		t.Errorf("CheckExpirations() domain DaysLeft expected to be `%v`, got `%v`", want, got)
+	}
+
+	if want, got := 2, len(report.Filter(ExpirationValid)); want != got {
+		t.Errorf("CheckExpirations() expected %v valid items, got %v", want, got)
+	}
+	if want, got := 3, len(report.AutoRenewDisabled()); want != got {
+		t.Errorf("CheckExpirations() expected %v items with auto-renew disabled, got %v", want, got)
+	}
+
+	report, err = CheckExpirations(client, "1010", &ExpirationReportOptions{Now: now.AddDate(1, 0, 0), Window: 60})
+	if err != nil {
+		t.Fatalf("CheckExpirations() returned error: %v", err)
+	}
+	if want, got := 3, len(report.Filter(ExpirationExpired)); want != got {
+		t.Errorf("CheckExpirations() expected %v expired items, got %v", want, got)
+	}
+}
+
+func TestCheckExpirations_ExpiredDomain(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	serveFixture(t, "/v2/1010/domains", "/api/listDomains/expired.http")
+	serveFixture(t, "/v2/1010/domains/example-beta.com/certificates", "/api/listCertificates/success.http")
+	serveFixture(t, "/v2/1010/domains/example-gamma.com/certificates", "/api/listCertificates/success.http")
+
+	now := time.Date(2015, 11, 20, 0, 0, 0, 0, time.UTC)
+	report, err := CheckExpirations(client, "1010", &ExpirationReportOptions{Now: now})
+	if err != nil {
+		t.Fatalf("CheckExpirations() returned error: %v", err)
+	}
+
+	expired := report.Filter(ExpirationExpired)
+	if want, got := 1, len(expired); want != got {
+		t.Fatalf("CheckExpirations() expected %v expired items, got %v", want, got)
+	}
+	if want, got := "example-gamma.com", expired[0].Name; want != got {
+		t.Errorf("CheckExpirations() expired item Name expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := "domain", expired[0].Kind; want != got {
+		t.Errorf("CheckExpirations() expired item Kind expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := -19, expired[0].DaysLeft; want != got {
+		t.Errorf("CheckExpirations() expired item DaysLeft expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestExpirationReport_Renderings(t *testing.T) {
+	report := &ExpirationReport{
+		AccountID: "1010",
+		Window:    30,
+		Items: []ExpirationItem{
+			{Kind: "domain", Name: `ex"ample.com`, Domain: "example.com", ExpiresOn: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC), DaysLeft: 10, Status: ExpirationExpiring},
+		},
+	}
+
+	text := &bytes.Buffer{}
+	if err := report.WriteText(text); err != nil {
+		t.Fatalf("WriteText() returned error: %v", err)
+	}
+	if !strings.Contains(text.String(), "expiring  domain  ex\"ample.com  2016-01-01  10") {
+		t.Errorf("WriteText() unexpected output:\n%v", text)
+	}
+
+	data := &bytes.Buffer{}
+	if err := report.WriteJSON(data); err != nil {
+		t.Fatalf("WriteJSON() returned error: %v", err)
+	}
+	decoded := &ExpirationReport{}
+	if err := json.Unmarshal(data.Bytes(), decoded); err != nil {
+		t.Fatalf("WriteJSON() produced invalid JSON: %v", err)
+	}
+	if want, got := ExpirationExpiring, decoded.Items[0].Status; want != got {
+		t.Errorf("WriteJSON() Status expected to be `%v`, got `%v`", want, got)
+	}
+
+	metrics := &bytes.Buffer{}
+	if err := report.WritePrometheus(metrics); err != nil {
+		t.Fatalf("WritePrometheus() returned error: %v", err)
+	}
+	for _, want := range []string{
+		"# TYPE dnsimple_expiration_timestamp_seconds gauge\n",
+		`dnsimple_expiration_timestamp_seconds{account="1010",kind="domain",name="ex\"ample.com",domain="example.com",status="expiring"} 1451606400` + "\n",
+		`dnsimple_expiration_days_left{account="1010",kind="domain",name="ex\"ample.com",domain="example.com",status="expiring"} 10` + "\n",
+		`dnsimple_expiration_auto_renew{account="1010",kind="domain",name="ex\"ample.com",domain="example.com",status="expiring"} 0` + "\n",
+	} {
+		if !strings.Contains(metrics.String(), want) {
+			t.Errorf("WritePrometheus() expected output to contain %q, got:\n%v", want, metrics)
+		}
+	}
+}
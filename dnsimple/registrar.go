@@ -91,6 +91,37 @@ func (s *RegistrarService) GetDomainPremiumPrice(accountID string, domainName st
 	return priceResponse, nil
 }
 
+// DomainPrice represents the prices of a domain, for one year.
+type DomainPrice struct {
+	Domain            string  `json:"domain"`
+	Premium           bool    `json:"premium"`
+	RegistrationPrice float64 `json:"registration_price"`
+	RenewalPrice      float64 `json:"renewal_price"`
+	TransferPrice     float64 `json:"transfer_price"`
+}
+
+// domainPriceResponse represents a response from a domain prices request.
+type domainPriceResponse struct {
+	Response
+	Data *DomainPrice `json:"data"`
+}
+
+// GetDomainPrices gets the registration, renewal and transfer prices of a domain.
+//
+// See https://developer.dnsimple.com/v2/registrar/#getDomainPrices
+func (s *RegistrarService) GetDomainPrices(accountID string, domainName string) (*domainPriceResponse, error) {
+	path := versioned(fmt.Sprintf("/%v/registrar/domains/%v/prices", accountID, domainName))
+	priceResponse := &domainPriceResponse{}
+
+	resp, err := s.client.get(path, priceResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	priceResponse.HttpResponse = resp
+	return priceResponse, nil
+}
+
 // DomainRegistration represents the result of a domain renewal call.
 type DomainRegistration struct {
 	ID           int    `json:"id"`
@@ -123,6 +154,8 @@ type DomainRegisterRequest struct {
 	EnableAutoRenewal bool `json:"auto_renew,omitempty"`
 	// Required as confirmation of the price, only if the domain is premium.
 	PremiumPrice string `json:"premium_price,omitempty"`
+	// Required by some TLDs. See TldsService.GetTldExtendedAttributes.
+	ExtendedAttributes map[string]string `json:"extended_attributes,omitempty"`
 }
 
 // RegisterDomain registers a domain name.
@@ -143,6 +176,22 @@ func (s *RegistrarService) RegisterDomain(accountID string, domainName string, r
 	return registrationResponse, nil
 }
 
+// GetDomainRegistration fetches a domain registration.
+//
+// See https://developer.dnsimple.com/v2/registrar/#getDomainRegistration
+func (s *RegistrarService) GetDomainRegistration(accountID string, domainName string, registrationID int) (*domainRegistrationResponse, error) {
+	path := versioned(fmt.Sprintf("/%v/registrar/domains/%v/registrations/%v", accountID, domainName, registrationID))
+	registrationResponse := &domainRegistrationResponse{}
+
+	resp, err := s.client.get(path, registrationResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	registrationResponse.HttpResponse = resp
+	return registrationResponse, nil
+}
+
 // DomainTransfer represents the result of a domain renewal call.
 type DomainTransfer struct {
 	ID           int    `json:"id"`
@@ -151,8 +200,10 @@ type DomainTransfer struct {
 	State        string `json:"state"`
 	AutoRenew    bool   `json:"auto_renew"`
 	WhoisPrivacy bool   `json:"whois_privacy"`
-	CreatedAt    string `json:"created_at,omitempty"`
-	UpdatedAt    string `json:"updated_at,omitempty"`
+	// The reason of the failure, when the transfer failed.
+	StatusDescription string `json:"status_description,omitempty"`
+	CreatedAt         string `json:"created_at,omitempty"`
+	UpdatedAt         string `json:"updated_at,omitempty"`
 }
 
 // domainTransferResponse represents a response from an API method that results in a domain transfer.
@@ -177,6 +228,8 @@ type DomainTransferRequest struct {
 	EnableAutoRenewal bool `json:"auto_renew,omitempty"`
 	// Required as confirmation of the price, only if the domain is premium.
 	PremiumPrice string `json:"premium_price,omitempty"`
+	// Required by some TLDs. See TldsService.GetTldExtendedAttributes.
+	ExtendedAttributes map[string]string `json:"extended_attributes,omitempty"`
 }
 
 // TransferDomain transfers a domain name.
@@ -197,6 +250,38 @@ func (s *RegistrarService) TransferDomain(accountID string, domainName string, r
 	return transferResponse, nil
 }
 
+// GetDomainTransfer fetches a domain transfer.
+//
+// See https://developer.dnsimple.com/v2/registrar/#getDomainTransfer
+func (s *RegistrarService) GetDomainTransfer(accountID string, domainName string, transferID int) (*domainTransferResponse, error) {
+	path := versioned(fmt.Sprintf("/%v/registrar/domains/%v/transfers/%v", accountID, domainName, transferID))
+	transferResponse := &domainTransferResponse{}
+
+	resp, err := s.client.get(path, transferResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	transferResponse.HttpResponse = resp
+	return transferResponse, nil
+}
+
+// CancelDomainTransfer cancels an in progress domain transfer.
+//
+// See https://developer.dnsimple.com/v2/registrar/#cancelDomainTransfer
+func (s *RegistrarService) CancelDomainTransfer(accountID string, domainName string, transferID int) (*domainTransferResponse, error) {
+	path := versioned(fmt.Sprintf("/%v/registrar/domains/%v/transfers/%v", accountID, domainName, transferID))
+	transferResponse := &domainTransferResponse{}
+
+	resp, err := s.client.delete(path, nil, transferResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	transferResponse.HttpResponse = resp
+	return transferResponse, nil
+}
+
 // domainTransferOutResponse represents a response from an API method that results in a domain transfer out.
 type domainTransferOutResponse struct {
 	Response
@@ -205,15 +290,23 @@ type domainTransferOutResponse struct {
 
 // Transfer out a domain name.
 //
+// The domain is unlocked and the authorization code is sent by email
+// to the registrant of the domain. The API may respond with no content,
+// in which case the response Data is nil.
+//
 // See https://developer.dnsimple.com/v2/registrar/#transfer-out
 func (s *RegistrarService) TransferDomainOut(accountID string, domainName string) (*domainTransferOutResponse, error) {
 	path := versioned(fmt.Sprintf("/%v/registrar/domains/%v/authorize_transfer_out", accountID, domainName))
 	transferResponse := &domainTransferOutResponse{}
 
-	resp, err := s.client.post(path, nil, nil)
+	body := &optionalJSON{}
+	resp, err := s.client.post(path, nil, body)
 	if err != nil {
 		return nil, err
 	}
+	if err := body.decode(transferResponse); err != nil {
+		return nil, err
+	}
 
 	transferResponse.HttpResponse = resp
 	return transferResponse, nil
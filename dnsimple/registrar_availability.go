@@ -0,0 +1,121 @@
+package dnsimple
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// DomainAvailability represents the availability and the registration price of a domain name.
+type DomainAvailability struct {
+	Domain    string
+	Available bool
+	Premium   bool
+
+	// The registration price for one year, only for available domains.
+	RegistrationPrice float64
+
+	// The premium registration price, only for available premium domains.
+	// It's the price to accept when registering the domain.
+	PremiumPrice string
+
+	// The error returned while checking the domain, if any.
+	Err error
+}
+
+// CheckDomainsAvailability checks the availability of the domain names using
+// a bounded pool of workers, and fetches the registration price of the
+// available domains, along with the premium price of the premium ones.
+//
+// The results are sent on the returned channel as soon as they complete,
+// in no particular order. The channel receives exactly one result per
+// domain name, and is closed when all the domain names have been checked.
+// Requests rejected because the rate limit has been exceeded are retried
+// once the rate limit window is reset.
+func CheckDomainsAvailability(ctx context.Context, c *Client, accountID string, domainNames []string, options *BulkOptions) <-chan DomainAvailability {
+	results := make(chan DomainAvailability, len(domainNames))
+	delivered := make([]bool, len(domainNames))
+
+	go func() {
+		defer close(results)
+
+		errs, _ := runBulk(ctx, len(domainNames), options, func(i int) (*http.Response, error) {
+			availability, resp, err := checkDomainAvailability(c, accountID, domainNames[i])
+			if err != nil {
+				// Rate limited requests are retried, the result is sent by the next attempt.
+				if !isRateLimited(err) {
+					delivered[i] = true
+					results <- DomainAvailability{Domain: domainNames[i], Err: err}
+				}
+				return nil, err
+			}
+
+			delivered[i] = true
+			results <- *availability
+			return resp, nil
+		})
+
+		// The items that were skipped, or interrupted while waiting for the rate limit.
+		for i, err := range errs {
+			if !delivered[i] {
+				results <- DomainAvailability{Domain: domainNames[i], Err: err}
+			}
+		}
+	}()
+
+	return results
+}
+
+func checkDomainAvailability(c *Client, accountID string, domainName string) (*DomainAvailability, *http.Response, error) {
+	checkResponse, err := c.Registrar.CheckDomain(accountID, domainName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	check := checkResponse.Data
+	availability := &DomainAvailability{Domain: domainName, Available: check.Available, Premium: check.Premium}
+	if !check.Available {
+		return availability, checkResponse.HttpResponse, nil
+	}
+
+	pricesResponse, err := c.Registrar.GetDomainPrices(accountID, domainName)
+	if err != nil {
+		return nil, nil, err
+	}
+	availability.RegistrationPrice = pricesResponse.Data.RegistrationPrice
+	if !check.Premium {
+		return availability, pricesResponse.HttpResponse, nil
+	}
+
+	priceResponse, err := c.Registrar.GetDomainPremiumPrice(accountID, domainName, &DomainPremiumPriceOptions{Action: "registration"})
+	if err != nil {
+		return nil, nil, err
+	}
+	availability.PremiumPrice = priceResponse.Data.PremiumPrice
+
+	return availability, priceResponse.HttpResponse, nil
+}
+
+func isRateLimited(err error) bool {
+	errorResponse, ok := err.(*ErrorResponse)
+	return ok && errorResponse.HttpResponse.StatusCode == http.StatusTooManyRequests
+}
+
+// CandidateDomainNames returns the label combined with every TLD
+// that accepts registrations.
+func CandidateDomainNames(c *Client, label string) ([]string, error) {
+	var names []string
+	err := eachPage(func(options ListOptions) (*Pagination, error) {
+		tldsResponse, err := c.Tlds.ListTlds(&options)
+		if err != nil {
+			return nil, err
+		}
+		for _, tld := range tldsResponse.Data {
+			if tld.RegistrationEnabled {
+				names = append(names, strings.ToLower(label)+"."+tld.Tld)
+			}
+		}
+		return tldsResponse.Pagination, nil
+	})
+	return names, err
+}
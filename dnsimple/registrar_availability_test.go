@@ -0,0 +1,94 @@
+package dnsimple
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckDomainsAvailability(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	defer func(pause time.Duration) { rateLimitMinPause = pause }(rateLimitMinPause)
+	rateLimitMinPause = 10 * time.Millisecond
+
+	serveFixture(t, "/v2/1010/registrar/domains/ruby.codes/check", "/api/checkDomain/success.http")
+	serveFixture(t, "/v2/1010/registrar/domains/ruby.codes/premium_price", "/api/getDomainPremiumPrice/success.http")
+	serveFixture(t, "/v2/1010/registrar/domains/ruby.codes/prices", "/api/getDomainPrices/success.http")
+	serveFixture(t, "/v2/1010/registrar/domains/example.io/prices", "/api/getDomainPrices/success.http")
+	serveFixture(t, "/v2/1010/registrar/domains/example.invalid/check", "/api/notfound-domain.http")
+
+	var requests int32
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/check", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"message":"Your account has exceeded the rate limit."}`)
+			return
+		}
+		io.WriteString(w, `{"data":{"domain":"example.com","available":false,"premium":false}}`)
+	})
+
+	mux.HandleFunc("/v2/1010/registrar/domains/example.io/check", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+
+		io.WriteString(w, `{"data":{"domain":"example.io","available":true,"premium":false}}`)
+	})
+	mux.HandleFunc("/v2/1010/registrar/domains/example.io/premium_price", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("CheckDomainsAvailability() not expected to fetch the premium price of example.io")
+	})
+
+	results := map[string]DomainAvailability{}
+	for result := range CheckDomainsAvailability(context.Background(), client, "1010", []string{"ruby.codes", "example.com", "example.io", "example.invalid"}, nil) {
+		if _, ok := results[result.Domain]; ok {
+			t.Errorf("CheckDomainsAvailability() returned %v more than once", result.Domain)
+		}
+		results[result.Domain] = result
+	}
+
+	if want, got := 4, len(results); want != got {
+		t.Fatalf("CheckDomainsAvailability() expected to return %v results, got %v", want, got)
+	}
+
+	if want, got := (DomainAvailability{Domain: "ruby.codes", Available: true, Premium: true, RegistrationPrice: 20.0, PremiumPrice: "109.00"}), results["ruby.codes"]; !reflect.DeepEqual(want, got) {
+		t.Errorf("CheckDomainsAvailability() expected ruby.codes to be `%+v`, got `%+v`", want, got)
+	}
+	if want, got := (DomainAvailability{Domain: "example.com"}), results["example.com"]; !reflect.DeepEqual(want, got) {
+		t.Errorf("CheckDomainsAvailability() expected example.com to be `%+v`, got `%+v`", want, got)
+	}
+	if want, got := (DomainAvailability{Domain: "example.io", Available: true, RegistrationPrice: 20.0}), results["example.io"]; !reflect.DeepEqual(want, got) {
+		t.Errorf("CheckDomainsAvailability() expected example.io to be `%+v`, got `%+v`", want, got)
+	}
+	if results["example.invalid"].Err == nil {
+		t.Errorf("CheckDomainsAvailability() expected example.invalid to report an error")
+	}
+	if want, got := int32(2), atomic.LoadInt32(&requests); want != got {
+		t.Errorf("CheckDomainsAvailability() expected the rate limited check to be retried, got %v requests", got)
+	}
+}
+
+func TestCandidateDomainNames(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/tlds", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testQuery(t, r, url.Values{"page": []string{"1"}, "per_page": []string{"100"}})
+
+		io.WriteString(w, `{"data":[{"tld":"com","registration_enabled":true},{"tld":"bv","registration_enabled":false},{"tld":"io","registration_enabled":true}],"pagination":{"current_page":1,"per_page":100,"total_entries":3,"total_pages":1}}`)
+	})
+
+	names, err := CandidateDomainNames(client, "Example")
+	if err != nil {
+		t.Fatalf("CandidateDomainNames() returned error: %v", err)
+	}
+
+	if want := []string{"example.com", "example.io"}; !reflect.DeepEqual(want, names) {
+		t.Errorf("CandidateDomainNames() expected to return `%v`, got `%v`", want, names)
+	}
+}
@@ -0,0 +1,141 @@
+package dnsimple
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DelegationError represents a delegation to in-bailiwick name servers
+// that have no glue records.
+type DelegationError struct {
+	Domain string
+
+	// The name servers without glue records.
+	Hosts []string
+
+	// The error returned while restoring the previous delegation, if any.
+	// When set, the domain may be delegated to name servers without glue records.
+	RollbackErr error
+}
+
+// Error implements the error interface.
+func (e *DelegationError) Error() string {
+	s := fmt.Sprintf("delegation of %v: no glue records for %v", e.Domain, strings.Join(e.Hosts, ", "))
+	if e.RollbackErr != nil {
+		s += fmt.Sprintf(" (rollback failed: %v)", e.RollbackErr)
+	}
+	return s
+}
+
+// ValidateDelegation checks that the name servers of the delegation that are
+// in the domain (e.g. ns1.example.com for example.com) have at least one glue
+// address among the vanity name servers, such as the ones returned by
+// RegistrarService.ChangeDomainDelegationToVanity. Without glue, such
+// a delegation can't be resolved.
+//
+// The returned error is a *DelegationError.
+func ValidateDelegation(domainName string, delegation Delegation, nameServers []VanityNameServer) error {
+	glued := map[string]bool{}
+	for _, nameServer := range nameServers {
+		if nameServer.IPv4 != "" || nameServer.IPv6 != "" {
+			glued[normalizeHostName(nameServer.Name)] = true
+		}
+	}
+
+	var missing []string
+	for _, host := range delegation {
+		if inBailiwick(host, domainName) && !glued[normalizeHostName(host)] {
+			missing = append(missing, host)
+		}
+	}
+
+	if len(missing) > 0 {
+		return &DelegationError{Domain: domainName, Hosts: missing}
+	}
+	return nil
+}
+
+// ChangeDomainDelegationWithGlue changes the name servers of the domain,
+// making sure that the name servers in the domain get glue records.
+//
+// The API only registers glue records for vanity name servers. A delegation
+// to name servers that are all in the domain is changed to vanity name servers,
+// and the returned vanity name servers are checked with ValidateDelegation:
+// when a name server has no glue, the previous delegation is restored and
+// a *DelegationError is returned. A delegation to name servers that are all
+// outside of the domain is changed as is, and no vanity name servers are returned.
+// A delegation that mixes both is refused with a *DelegationError, without
+// changing the delegation.
+func ChangeDomainDelegationWithGlue(c *Client, accountID string, domainName string, delegation Delegation) ([]VanityNameServer, error) {
+	if len(delegation) == 0 {
+		return nil, fmt.Errorf("delegation of %v: no name servers", domainName)
+	}
+
+	var inside []string
+	for _, host := range delegation {
+		if inBailiwick(host, domainName) {
+			inside = append(inside, host)
+		}
+	}
+
+	switch len(inside) {
+	case 0:
+		if _, err := c.Registrar.ChangeDomainDelegation(accountID, domainName, &delegation); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	case len(delegation):
+	default:
+		return nil, &DelegationError{Domain: domainName, Hosts: inside}
+	}
+
+	previousResponse, err := c.Registrar.GetDomainDelegation(accountID, domainName)
+	if err != nil {
+		return nil, fmt.Errorf("getting the delegation of %v: %v", domainName, err)
+	}
+
+	delegationResponse, err := c.Registrar.ChangeDomainDelegationToVanity(accountID, domainName, &delegation)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateDelegation(domainName, delegation, delegationResponse.Data); err != nil {
+		delegationErr := err.(*DelegationError)
+		delegationErr.RollbackErr = restoreDomainDelegation(c, accountID, domainName, *previousResponse.Data)
+		return nil, delegationErr
+	}
+	return delegationResponse.Data, nil
+}
+
+// restoreDomainDelegation reverts a change to vanity name servers.
+// A previous delegation to name servers in the domain is restored as vanity name servers.
+func restoreDomainDelegation(c *Client, accountID string, domainName string, previous Delegation) error {
+	vanity := len(previous) > 0
+	for _, host := range previous {
+		if !inBailiwick(host, domainName) {
+			vanity = false
+		}
+	}
+	if vanity {
+		_, err := c.Registrar.ChangeDomainDelegationToVanity(accountID, domainName, &previous)
+		return err
+	}
+
+	if _, err := c.Registrar.ChangeDomainDelegationFromVanity(accountID, domainName); err != nil {
+		return err
+	}
+	if len(previous) == 0 {
+		return nil
+	}
+	_, err := c.Registrar.ChangeDomainDelegation(accountID, domainName, &previous)
+	return err
+}
+
+// inBailiwick returns true if the host is the domain itself or a name under the domain.
+func inBailiwick(host string, domainName string) bool {
+	host, domainName = normalizeHostName(host), normalizeHostName(domainName)
+	return host == domainName || strings.HasSuffix(host, "."+domainName)
+}
+
+func normalizeHostName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
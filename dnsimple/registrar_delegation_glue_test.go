@@ -0,0 +1,163 @@
+package dnsimple
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestValidateDelegation(t *testing.T) {
+	nameServers := []VanityNameServer{
+		{Name: "ns1.example.com", IPv4: "192.0.2.1"},
+		{Name: "ns2.example.com"},
+	}
+
+	if err := ValidateDelegation("example.com", Delegation{"NS1.example.com.", "ns1.dnsimple.com", "ns.example.net"}, nameServers); err != nil {
+		t.Errorf("ValidateDelegation() returned error: %v", err)
+	}
+
+	err := ValidateDelegation("example.com", Delegation{"ns1.example.com", "ns2.example.com", "ns3.example.com", "ns1.notexample.com"}, nameServers)
+	delegationErr, ok := err.(*DelegationError)
+	if !ok {
+		t.Fatalf("ValidateDelegation() expected to return a *DelegationError, got `%v`", err)
+	}
+	if want, got := []string{"ns2.example.com", "ns3.example.com"}, delegationErr.Hosts; !reflect.DeepEqual(want, got) {
+		t.Errorf("ValidateDelegation() returned Hosts expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestChangeDomainDelegationWithGlue(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	var requests []string
+	fixtures := map[string]string{
+		"GET /v2/1010/registrar/domains/example.com/delegation":           "/api/getDomainDelegation/success.http",
+		"PUT /v2/1010/registrar/domains/example.com/delegation":           "/api/changeDomainDelegation/success.http",
+		"PUT /v2/1010/registrar/domains/example.com/delegation/vanity":    "/api/changeDomainDelegationToVanity/success.http",
+		"DELETE /v2/1010/registrar/domains/example.com/delegation/vanity": "/api/changeDomainDelegationFromVanity/success.http",
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		request := r.Method + " " + r.URL.Path
+		requests = append(requests, request)
+
+		fixture, ok := fixtures[request]
+		if !ok {
+			t.Fatalf("Unexpected request %v", request)
+		}
+		httpResponse := httpResponseFixture(t, fixture)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	}
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/delegation", handler)
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/delegation/vanity", handler)
+
+	_, err := ChangeDomainDelegationWithGlue(client, "1010", "example.com", Delegation{})
+	if err == nil {
+		t.Fatalf("ChangeDomainDelegationWithGlue() expected to return an error for an empty delegation")
+	}
+
+	_, err = ChangeDomainDelegationWithGlue(client, "1010", "example.com", Delegation{"ns1.example.com", "ns1.dnsimple.com"})
+	delegationErr, ok := err.(*DelegationError)
+	if !ok {
+		t.Fatalf("ChangeDomainDelegationWithGlue() expected to return a *DelegationError, got `%v`", err)
+	}
+	if want, got := []string{"ns1.example.com"}, delegationErr.Hosts; !reflect.DeepEqual(want, got) {
+		t.Errorf("ChangeDomainDelegationWithGlue() returned Hosts expected to be `%v`, got `%v`", want, got)
+	}
+	if len(requests) > 0 {
+		t.Fatalf("ChangeDomainDelegationWithGlue() expected not to change the delegation, got %v", requests)
+	}
+
+	nameServers, err := ChangeDomainDelegationWithGlue(client, "1010", "example.com", Delegation{"ns1.example.com", "ns2.example.com"})
+	if err != nil {
+		t.Fatalf("ChangeDomainDelegationWithGlue() returned error: %v", err)
+	}
+	if want, got := 2, len(nameServers); want != got {
+		t.Errorf("ChangeDomainDelegationWithGlue() expected to return %v vanity name servers, got %v", want, got)
+	}
+
+	nameServers, err = ChangeDomainDelegationWithGlue(client, "1010", "example.com", Delegation{"ns1.dnsimple.com", "ns2.dnsimple.com"})
+	if err != nil {
+		t.Fatalf("ChangeDomainDelegationWithGlue() returned error: %v", err)
+	}
+	if nameServers != nil {
+		t.Errorf("ChangeDomainDelegationWithGlue() expected to return no vanity name servers, got %v", nameServers)
+	}
+
+	want := []string{
+		"GET /v2/1010/registrar/domains/example.com/delegation",
+		"PUT /v2/1010/registrar/domains/example.com/delegation/vanity",
+		"PUT /v2/1010/registrar/domains/example.com/delegation",
+	}
+	if !reflect.DeepEqual(want, requests) {
+		t.Errorf("ChangeDomainDelegationWithGlue() requests = %#v, want %#v", requests, want)
+	}
+}
+
+func TestChangeDomainDelegationWithGlue_Rollback(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	var requests []string
+	var restored Delegation
+	respond := func(w http.ResponseWriter, fixture string) {
+		httpResponse := httpResponseFixture(t, fixture)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	}
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/delegation", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		if r.Method == "PUT" {
+			json.NewDecoder(r.Body).Decode(&restored)
+			respond(w, "/api/changeDomainDelegation/success.http")
+			return
+		}
+		testMethod(t, r, "GET")
+		respond(w, "/api/getDomainDelegation/success.http")
+	})
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/delegation/vanity", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		if r.Method == "DELETE" {
+			respond(w, "/api/changeDomainDelegationFromVanity/success.http")
+			return
+		}
+		testMethod(t, r, "PUT")
+		fmt.Fprint(w, `{"data":[{"id":1,"name":"ns1.example.com","ipv4":"127.0.0.1"},{"id":2,"name":"ns2.example.com"}]}`)
+	})
+
+	nameServers, err := ChangeDomainDelegationWithGlue(client, "1010", "example.com", Delegation{"ns1.example.com", "ns2.example.com"})
+	delegationErr, ok := err.(*DelegationError)
+	if !ok {
+		t.Fatalf("ChangeDomainDelegationWithGlue() expected to return a *DelegationError, got `%v`", err)
+	}
+	if want, got := []string{"ns2.example.com"}, delegationErr.Hosts; !reflect.DeepEqual(want, got) {
+		t.Errorf("ChangeDomainDelegationWithGlue() returned Hosts expected to be `%v`, got `%v`", want, got)
+	}
+	if delegationErr.RollbackErr != nil {
+		t.Errorf("ChangeDomainDelegationWithGlue() returned RollbackErr: %v", delegationErr.RollbackErr)
+	}
+	if nameServers != nil {
+		t.Errorf("ChangeDomainDelegationWithGlue() expected to return no vanity name servers, got %v", nameServers)
+	}
+
+	wantRequests := []string{
+		"GET /v2/1010/registrar/domains/example.com/delegation",
+		"PUT /v2/1010/registrar/domains/example.com/delegation/vanity",
+		"DELETE /v2/1010/registrar/domains/example.com/delegation/vanity",
+		"PUT /v2/1010/registrar/domains/example.com/delegation",
+	}
+	if !reflect.DeepEqual(wantRequests, requests) {
+		t.Errorf("ChangeDomainDelegationWithGlue() requests = %#v, want %#v", requests, wantRequests)
+	}
+	if want := (Delegation{"ns1.dnsimple.com", "ns2.dnsimple.com", "ns3.dnsimple.com", "ns4.dnsimple.com"}); !reflect.DeepEqual(want, restored) {
+		t.Errorf("ChangeDomainDelegationWithGlue() restored delegation expected to be `%v`, got `%v`", want, restored)
+	}
+}
@@ -0,0 +1,139 @@
+package dnsimple
+
+import (
+	"fmt"
+)
+
+// DomainPolicy represents the registrar settings every registered domain
+// of an account is expected to have.
+type DomainPolicy struct {
+	// Require auto-renewal to be enabled.
+	RequireAutoRenew bool
+
+	// Require WHOIS privacy to be enabled, where the TLD supports it.
+	RequireWhoisPrivacy bool
+
+	// Set to true to fix the violations. By default they are only reported.
+	Fix bool
+}
+
+// PolicySetting identifies a setting checked by the policy.
+type PolicySetting string
+
+const (
+	// PolicyAutoRenew is the setting of the automatic renewal of a domain.
+	PolicyAutoRenew = PolicySetting("auto_renew")
+
+	// PolicyWhoisPrivacy is the setting of the WHOIS privacy of a domain.
+	PolicyWhoisPrivacy = PolicySetting("whois_privacy")
+)
+
+// PolicyViolation represents a domain setting that doesn't match the policy.
+type PolicyViolation struct {
+	Domain  string
+	Setting PolicySetting
+
+	// Set to true when the violation has been fixed.
+	Fixed bool
+
+	// The error returned while fixing the violation, if any.
+	Err error
+}
+
+// String returns a human-readable description of the violation.
+func (v PolicyViolation) String() string {
+	s := fmt.Sprintf("%v: %v is disabled", v.Domain, v.Setting)
+	switch {
+	case v.Err != nil:
+		s += fmt.Sprintf(" (fix failed: %v)", v.Err)
+	case v.Fixed:
+		s += " (fixed)"
+	}
+	return s
+}
+
+// PolicyReport represents the result of the evaluation of a policy across an account.
+type PolicyReport struct {
+	// The number of registered domains evaluated.
+	Checked int
+
+	Violations []PolicyViolation
+
+	// The settings that were not checked because the TLD doesn't support
+	// changing them, keyed by domain name.
+	Exempt map[string][]PolicySetting
+}
+
+// Errors returns the violations that could not be fixed.
+func (r *PolicyReport) Errors() []PolicyViolation {
+	var violations []PolicyViolation
+	for _, violation := range r.Violations {
+		if violation.Err != nil {
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+// EnforceDomainPolicy evaluates the policy across the registered domains of an account,
+// and fixes the violations if the policy says so.
+//
+// Auto-renewal is not checked for the TLDs where it can't be changed (Tld.AutoRenewOnly),
+// and WHOIS privacy is not checked for the TLDs that don't support it.
+// The errors returned while fixing a violation are reported in the violation.
+func EnforceDomainPolicy(c *Client, accountID string, policy DomainPolicy) (*PolicyReport, error) {
+	domains, err := listAllDomains(c, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("listing domains: %v", err)
+	}
+
+	report := &PolicyReport{Exempt: map[string][]PolicySetting{}}
+	tlds := map[string]*Tld{}
+	for _, domain := range domains {
+		if domain.State != "registered" {
+			continue
+		}
+		report.Checked++
+
+		tldName := domainTld(domain.Name)
+		tld, ok := tlds[tldName]
+		if !ok {
+			tldResponse, err := c.Tlds.GetTld(tldName)
+			if err != nil {
+				return nil, fmt.Errorf("getting TLD %v: %v", tldName, err)
+			}
+			tld = tldResponse.Data
+			tlds[tldName] = tld
+		}
+
+		if policy.RequireAutoRenew {
+			switch {
+			case tld.AutoRenewOnly:
+				report.Exempt[domain.Name] = append(report.Exempt[domain.Name], PolicyAutoRenew)
+			case !domain.AutoRenew:
+				violation := PolicyViolation{Domain: domain.Name, Setting: PolicyAutoRenew}
+				if policy.Fix {
+					_, violation.Err = c.Registrar.EnableDomainAutoRenewal(accountID, domain.Name)
+					violation.Fixed = violation.Err == nil
+				}
+				report.Violations = append(report.Violations, violation)
+			}
+		}
+
+		if policy.RequireWhoisPrivacy {
+			switch {
+			case !tld.WhoisPrivacy:
+				report.Exempt[domain.Name] = append(report.Exempt[domain.Name], PolicyWhoisPrivacy)
+			case !domain.PrivateWhois:
+				violation := PolicyViolation{Domain: domain.Name, Setting: PolicyWhoisPrivacy}
+				if policy.Fix {
+					_, violation.Err = c.Registrar.EnableWhoisPrivacy(accountID, domain.Name)
+					violation.Fixed = violation.Err == nil
+				}
+				report.Violations = append(report.Violations, violation)
+			}
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,80 @@
+package dnsimple
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestEnforceDomainPolicy(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	serveFixture(t, "/v2/1010/domains", "/api/listDomains/success.http")
+	serveFixture(t, "/v2/tlds/com", "/api/getTld/success.http")
+	mux.HandleFunc("/v2/1010/registrar/domains/example-beta.com/auto_renewal", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/enableDomainAutoRenewal/success.http")
+
+		testMethod(t, r, "PUT")
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+	mux.HandleFunc("/v2/1010/registrar/domains/example-beta.com/whois_privacy", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/enableWhoisPrivacy/success.http")
+
+		testMethod(t, r, "PUT")
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	report, err := EnforceDomainPolicy(client, "1010", DomainPolicy{RequireAutoRenew: true, RequireWhoisPrivacy: true, Fix: true})
+	if err != nil {
+		t.Fatalf("EnforceDomainPolicy() returned error: %v", err)
+	}
+
+	if want, got := 1, report.Checked; want != got {
+		t.Errorf("EnforceDomainPolicy() expected to check %v domains, got %v", want, got)
+	}
+	want := []PolicyViolation{
+		{Domain: "example-beta.com", Setting: PolicyAutoRenew, Fixed: true},
+		{Domain: "example-beta.com", Setting: PolicyWhoisPrivacy, Fixed: true},
+	}
+	if !reflect.DeepEqual(want, report.Violations) {
+		t.Errorf("EnforceDomainPolicy() violations expected to be `%+v`, got `%+v`", want, report.Violations)
+	}
+	if want, got := 0, len(report.Errors()); want != got {
+		t.Errorf("EnforceDomainPolicy() expected %v errors, got %v", want, got)
+	}
+}
+
+func TestEnforceDomainPolicy_Exempt(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	serveFixture(t, "/v2/1010/domains", "/api/listDomains/success.http")
+	mux.HandleFunc("/v2/tlds/com", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"data":{"tld":"com","whois_privacy":false,"auto_renew_only":true}}`)
+	})
+
+	report, err := EnforceDomainPolicy(client, "1010", DomainPolicy{RequireAutoRenew: true, RequireWhoisPrivacy: true})
+	if err != nil {
+		t.Fatalf("EnforceDomainPolicy() returned error: %v", err)
+	}
+
+	if want, got := 0, len(report.Violations); want != got {
+		t.Errorf("EnforceDomainPolicy() expected %v violations, got %v", want, got)
+	}
+	if want, got := []PolicySetting{PolicyAutoRenew, PolicyWhoisPrivacy}, report.Exempt["example-beta.com"]; !reflect.DeepEqual(want, got) {
+		t.Errorf("EnforceDomainPolicy() exempt settings expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestPolicyViolation_String(t *testing.T) {
+	violation := PolicyViolation{Domain: "example.com", Setting: PolicyWhoisPrivacy, Fixed: true}
+	if want, got := "example.com: whois_privacy is disabled (fixed)", violation.String(); want != got {
+		t.Errorf("String() expected to be `%v`, got `%v`", want, got)
+	}
+}
@@ -0,0 +1,224 @@
+package dnsimple
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultRegistrationPollInterval is the interval between two checks
+// of the state of a pending registration.
+var defaultRegistrationPollInterval = 10 * time.Second
+
+// RegistrationStep identifies a step of the registration workflow.
+type RegistrationStep string
+
+const (
+	// RegistrationStepCheck is the step that checks the availability of the domain.
+	RegistrationStepCheck = RegistrationStep("check")
+
+	// RegistrationStepPremiumPrice is the step that accepts the premium price of the domain.
+	RegistrationStepPremiumPrice = RegistrationStep("premium_price")
+
+	// RegistrationStepContact is the step that checks the registrant contact.
+	RegistrationStepContact = RegistrationStep("contact")
+
+	// RegistrationStepExtendedAttributes is the step that checks the extended attributes of the TLD.
+	RegistrationStepExtendedAttributes = RegistrationStep("extended_attributes")
+
+	// RegistrationStepRegister is the step that requests the registration.
+	RegistrationStepRegister = RegistrationStep("register")
+
+	// RegistrationStepWait is the step that waits for a pending registration to complete.
+	RegistrationStepWait = RegistrationStep("wait")
+)
+
+// RegistrationError represents a failure of a step of the registration workflow.
+type RegistrationError struct {
+	Domain string
+	Step   RegistrationStep
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *RegistrationError) Error() string {
+	return fmt.Sprintf("registering %v: %v: %v", e.Domain, e.Step, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RegistrationError) Unwrap() error {
+	return e.Err
+}
+
+// DomainRegistrationOptions specifies the parameters of the registration workflow.
+type DomainRegistrationOptions struct {
+	// The ID of the Contact to use as registrant for the domain. Required.
+	RegistrantID int
+
+	EnableWhoisPrivacy bool
+	EnableAutoRenewal  bool
+
+	// The maximum premium price accepted for the registration.
+	// Premium domains are rejected when empty.
+	MaxPremiumPrice string
+
+	// The extended attributes required by the TLD, if any.
+	ExtendedAttributes map[string]string
+
+	// The interval between two checks of the registration state.
+	// Defaults to 10 seconds.
+	PollInterval time.Duration
+}
+
+// DomainRegistrationOutcome represents the result of a completed registration workflow.
+type DomainRegistrationOutcome struct {
+	Check *DomainCheck
+
+	// The premium price paid for the registration, when the domain is premium.
+	PremiumPrice string
+
+	// The registration, in its final state.
+	Registration *DomainRegistration
+}
+
+// RegisterDomainAndWait registers a domain and waits for the registration to complete.
+//
+// The availability of the domain, the premium price, the registrant contact
+// and the extended attributes required by the TLD are checked before
+// the registration is submitted. The registration state is then polled
+// until it leaves the new and registering states, or the context is done.
+//
+// A failure of any step is returned as a *RegistrationError.
+func RegisterDomainAndWait(ctx context.Context, c *Client, accountID string, domainName string, options DomainRegistrationOptions) (*DomainRegistrationOutcome, error) {
+	outcome := &DomainRegistrationOutcome{}
+	fail := func(step RegistrationStep, err error) (*DomainRegistrationOutcome, error) {
+		return outcome, &RegistrationError{Domain: domainName, Step: step, Err: err}
+	}
+
+	checkResponse, err := c.Registrar.CheckDomain(accountID, domainName)
+	if err != nil {
+		return fail(RegistrationStepCheck, err)
+	}
+	outcome.Check = checkResponse.Data
+	if !outcome.Check.Available {
+		return fail(RegistrationStepCheck, fmt.Errorf("domain is not available"))
+	}
+
+	if outcome.Check.Premium {
+		price, err := acceptPremiumPrice(c, accountID, domainName, options.MaxPremiumPrice)
+		if err != nil {
+			return fail(RegistrationStepPremiumPrice, err)
+		}
+		outcome.PremiumPrice = price
+	}
+
+	contactResponse, err := c.Contacts.GetContact(accountID, int64(options.RegistrantID))
+	if err != nil {
+		return fail(RegistrationStepContact, err)
+	}
+	if missing := missingContactFields(contactResponse.Data); len(missing) > 0 {
+		return fail(RegistrationStepContact, fmt.Errorf("registrant contact is missing %v", strings.Join(missing, ", ")))
+	}
+
+	attributesResponse, err := c.Tlds.GetTldExtendedAttributes(domainTld(domainName))
+	if err != nil {
+		return fail(RegistrationStepExtendedAttributes, err)
+	}
+	if err := ValidateExtendedAttributes(attributesResponse.Data, options.ExtendedAttributes); err != nil {
+		return fail(RegistrationStepExtendedAttributes, err)
+	}
+
+	registrationResponse, err := c.Registrar.RegisterDomain(accountID, domainName, &DomainRegisterRequest{
+		RegistrantID:       options.RegistrantID,
+		EnableWhoisPrivacy: options.EnableWhoisPrivacy,
+		EnableAutoRenewal:  options.EnableAutoRenewal,
+		PremiumPrice:       outcome.PremiumPrice,
+		ExtendedAttributes: options.ExtendedAttributes,
+	})
+	if err != nil {
+		return fail(RegistrationStepRegister, err)
+	}
+	outcome.Registration = registrationResponse.Data
+
+	interval := options.PollInterval
+	if interval <= 0 {
+		interval = defaultRegistrationPollInterval
+	}
+	for outcome.Registration.State == "new" || outcome.Registration.State == "registering" {
+		if err := sleep(ctx, interval); err != nil {
+			return fail(RegistrationStepWait, err)
+		}
+
+		registrationResponse, err = c.Registrar.GetDomainRegistration(accountID, domainName, outcome.Registration.ID)
+		if err != nil {
+			return fail(RegistrationStepWait, err)
+		}
+		outcome.Registration = registrationResponse.Data
+	}
+
+	if outcome.Registration.State != "registered" {
+		return fail(RegistrationStepWait, fmt.Errorf("registration ended in state %v", outcome.Registration.State))
+	}
+
+	return outcome, nil
+}
+
+// acceptPremiumPrice fetches the registration price of a premium domain,
+// and returns it if it doesn't exceed the maximum price.
+func acceptPremiumPrice(c *Client, accountID string, domainName string, maxPrice string) (string, error) {
+	if maxPrice == "" {
+		return "", fmt.Errorf("domain is premium and no maximum premium price is set")
+	}
+	max, err := strconv.ParseFloat(maxPrice, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid maximum premium price %v: %v", maxPrice, err)
+	}
+
+	priceResponse, err := c.Registrar.GetDomainPremiumPrice(accountID, domainName, &DomainPremiumPriceOptions{Action: "registration"})
+	if err != nil {
+		return "", err
+	}
+
+	price := priceResponse.Data.PremiumPrice
+	value, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid premium price %v: %v", price, err)
+	}
+	if value > max {
+		return "", fmt.Errorf("premium price %v exceeds the maximum price %v", price, maxPrice)
+	}
+
+	return price, nil
+}
+
+// missingContactFields returns the fields required by registries that are not set in the contact.
+func missingContactFields(contact *Contact) []string {
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"first_name", contact.FirstName},
+		{"last_name", contact.LastName},
+		{"address1", contact.Address1},
+		{"city", contact.City},
+		{"postal_code", contact.PostalCode},
+		{"country", contact.Country},
+		{"phone", contact.Phone},
+		{"email", contact.Email},
+	}
+
+	var missing []string
+	for _, field := range fields {
+		if strings.TrimSpace(field.value) == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	return missing
+}
+
+// domainTld returns the TLD of a domain name.
+func domainTld(domainName string) string {
+	return domainName[strings.Index(domainName, ".")+1:]
+}
@@ -0,0 +1,94 @@
+package dnsimple
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func setupRegistrationFixtures(t *testing.T, domainName string, attributesFixture string) {
+	serveFixture(t, "/v2/1010/registrar/domains/"+domainName+"/check", "/api/checkDomain/success.http")
+	serveFixture(t, "/v2/1010/registrar/domains/"+domainName+"/premium_price", "/api/getDomainPremiumPrice/success.http")
+	serveFixture(t, "/v2/tlds/"+domainTld(domainName)+"/extended_attributes", attributesFixture)
+}
+
+func TestRegisterDomainAndWait(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	serveFixture(t, "/v2/1010/contacts/1", "/api/getContact/success.http")
+	setupRegistrationFixtures(t, "ruby.codes", "/api/getTldExtendedAttributes/success-noattributes.http")
+	mux.HandleFunc("/v2/1010/registrar/domains/ruby.codes/registrations", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/registerDomain/success.http")
+
+		testMethod(t, r, "POST")
+		testRequestJSON(t, r, map[string]interface{}{"registrant_id": float64(1), "auto_renew": true, "premium_price": "109.00"})
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+	polls := 0
+	mux.HandleFunc("/v2/1010/registrar/domains/ruby.codes/registrations/1", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls == 1 {
+			httpResponse := httpResponseFixture(t, "/api/getDomainRegistration/success.http")
+			w.WriteHeader(httpResponse.StatusCode)
+			io.Copy(w, httpResponse.Body)
+			return
+		}
+		w.Write([]byte(`{"data":{"id":1,"domain_id":999,"registrant_id":1,"period":1,"state":"registered"}}`))
+	})
+
+	outcome, err := RegisterDomainAndWait(context.Background(), client, "1010", "ruby.codes", DomainRegistrationOptions{
+		RegistrantID:      1,
+		EnableAutoRenewal: true,
+		MaxPremiumPrice:   "150",
+		PollInterval:      time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("RegisterDomainAndWait() returned error: %v", err)
+	}
+
+	if want, got := "109.00", outcome.PremiumPrice; want != got {
+		t.Errorf("RegisterDomainAndWait() PremiumPrice expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := "registered", outcome.Registration.State; want != got {
+		t.Errorf("RegisterDomainAndWait() Registration.State expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := 2, polls; want != got {
+		t.Errorf("RegisterDomainAndWait() expected to poll %v times, got %v", want, got)
+	}
+}
+
+func TestRegisterDomainAndWait_Failures(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	serveFixture(t, "/v2/1010/contacts/1", "/api/getContact/success.http")
+	setupRegistrationFixtures(t, "ruby.codes", "/api/getTldExtendedAttributes/success-noattributes.http")
+	setupRegistrationFixtures(t, "example.uk", "/api/getTldExtendedAttributes/success-attributes.http")
+
+	cases := []struct {
+		domainName string
+		options    DomainRegistrationOptions
+		step       RegistrationStep
+	}{
+		{"ruby.codes", DomainRegistrationOptions{RegistrantID: 1}, RegistrationStepPremiumPrice},
+		{"ruby.codes", DomainRegistrationOptions{RegistrantID: 1, MaxPremiumPrice: "100"}, RegistrationStepPremiumPrice},
+		{"example.uk", DomainRegistrationOptions{RegistrantID: 1, MaxPremiumPrice: "150"}, RegistrationStepExtendedAttributes},
+	}
+
+	for _, c := range cases {
+		_, err := RegisterDomainAndWait(context.Background(), client, "1010", c.domainName, c.options)
+
+		registrationErr, ok := err.(*RegistrationError)
+		if !ok {
+			t.Fatalf("RegisterDomainAndWait(%v) expected to return a *RegistrationError, got %v", c.domainName, err)
+		}
+		if want, got := c.step, registrationErr.Step; want != got {
+			t.Errorf("RegisterDomainAndWait(%v) Step expected to be `%v`, got `%v` (%v)", c.domainName, want, got, err)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package dnsimple
+
+import (
+	"context"
+	"fmt"
+)
+
+// RegistrantChange represents a registrant change in DNSimple.
+type RegistrantChange struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	DomainID  int64 `json:"domain_id"`
+	ContactID int64 `json:"contact_id"`
+
+	// The state of the change: new, pending, completed, cancelling or cancelled.
+	// A pending change is waiting for the confirmation of the registrant.
+	State string `json:"state"`
+
+	ExtendedAttributes map[string]string `json:"extended_attributes"`
+
+	// Set to true if the change is a change of ownership for the registry.
+	RegistryOwnerChange bool `json:"registry_owner_change"`
+
+	// The party that lifted the inter-registrar transfer lock, if any.
+	IrtLockLiftedBy string `json:"irt_lock_lifted_by"`
+
+	CreatedAt string `json:"created_at,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// Pending returns true if the change is waiting for the confirmation of the registrant.
+func (c *RegistrantChange) Pending() bool {
+	return c.State == "pending"
+}
+
+// RegistrantChangeCheck represents the requirements of a registrant change.
+type RegistrantChangeCheck struct {
+	DomainID  int64 `json:"domain_id"`
+	ContactID int64 `json:"contact_id"`
+
+	// The extended attributes required by the TLD to change the registrant.
+	ExtendedAttributes []TldExtendedAttribute `json:"extended_attributes"`
+
+	RegistryOwnerChange bool `json:"registry_owner_change"`
+}
+
+// RegistrantChangeCheckRequest represents the attributes you can pass to a registrant change check API request.
+type RegistrantChangeCheckRequest struct {
+	DomainID  int64 `json:"domain_id"`
+	ContactID int64 `json:"contact_id"`
+}
+
+// RegistrantChangeRequest represents the attributes you can pass to a registrant change API request.
+type RegistrantChangeRequest struct {
+	DomainID  int64 `json:"domain_id"`
+	ContactID int64 `json:"contact_id"`
+	// Required by some TLDs. See RegistrarService.CheckRegistrantChange.
+	ExtendedAttributes map[string]string `json:"extended_attributes,omitempty"`
+}
+
+// RegistrantChangeListOptions specifies the optional parameters you can provide
+// to customize the RegistrarService.ListRegistrantChanges method.
+type RegistrantChangeListOptions struct {
+	// Select the changes of the given domain ID.
+	DomainID int64 `url:"domain_id,omitempty"`
+
+	// Select the changes to the given contact ID.
+	ContactID int64 `url:"contact_id,omitempty"`
+
+	// Select the changes in the given state.
+	State string `url:"state,omitempty"`
+
+	ListOptions
+}
+
+// registrantChangeResponse represents a response from an API method that returns a RegistrantChange struct.
+type registrantChangeResponse struct {
+	Response
+	Data *RegistrantChange `json:"data"`
+}
+
+// registrantChangesResponse represents a response from an API method that returns a collection of RegistrantChange struct.
+type registrantChangesResponse struct {
+	Response
+	Data []RegistrantChange `json:"data"`
+}
+
+// registrantChangeCheckResponse represents a response from a registrant change check request.
+type registrantChangeCheckResponse struct {
+	Response
+	Data *RegistrantChangeCheck `json:"data"`
+}
+
+func registrantChangePath(accountID string, changeID int64) (path string) {
+	path = fmt.Sprintf("/%v/registrar/registrant_changes", accountID)
+	if changeID != 0 {
+		path += fmt.Sprintf("/%v", changeID)
+	}
+	return
+}
+
+// ListRegistrantChanges lists the registrant changes for an account.
+//
+// See https://developer.dnsimple.com/v2/registrar/#listRegistrantChanges
+func (s *RegistrarService) ListRegistrantChanges(accountID string, options *RegistrantChangeListOptions) (*registrantChangesResponse, error) {
+	path := versioned(registrantChangePath(accountID, 0))
+	changesResponse := &registrantChangesResponse{}
+
+	path, err := addURLQueryOptions(path, options)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.get(path, changesResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	changesResponse.HttpResponse = resp
+	return changesResponse, nil
+}
+
+// CheckRegistrantChange returns the requirements to change the registrant of a domain.
+//
+// See https://developer.dnsimple.com/v2/registrar/#checkRegistrantChange
+func (s *RegistrarService) CheckRegistrantChange(accountID string, request *RegistrantChangeCheckRequest) (*registrantChangeCheckResponse, error) {
+	path := versioned(registrantChangePath(accountID, 0) + "/check")
+	checkResponse := &registrantChangeCheckResponse{}
+
+	resp, err := s.client.post(path, request, checkResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	checkResponse.HttpResponse = resp
+	return checkResponse, nil
+}
+
+// CreateRegistrantChange starts the change of the registrant of a domain.
+//
+// See https://developer.dnsimple.com/v2/registrar/#createRegistrantChange
+func (s *RegistrarService) CreateRegistrantChange(accountID string, request *RegistrantChangeRequest) (*registrantChangeResponse, error) {
+	path := versioned(registrantChangePath(accountID, 0))
+	changeResponse := &registrantChangeResponse{}
+
+	resp, err := s.client.post(path, request, changeResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	changeResponse.HttpResponse = resp
+	return changeResponse, nil
+}
+
+// GetRegistrantChange fetches a registrant change.
+//
+// See https://developer.dnsimple.com/v2/registrar/#getRegistrantChange
+func (s *RegistrarService) GetRegistrantChange(accountID string, changeID int64) (*registrantChangeResponse, error) {
+	path := versioned(registrantChangePath(accountID, changeID))
+	changeResponse := &registrantChangeResponse{}
+
+	resp, err := s.client.get(path, changeResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	changeResponse.HttpResponse = resp
+	return changeResponse, nil
+}
+
+// CancelRegistrantChange cancels a registrant change that is not completed yet.
+//
+// When the cancellation is asynchronous the API returns the change,
+// in the cancelling state. When the change is cancelled immediately
+// the API returns no content, and the response Data is nil.
+//
+// See https://developer.dnsimple.com/v2/registrar/#deleteRegistrantChange
+func (s *RegistrarService) CancelRegistrantChange(accountID string, changeID int64) (*registrantChangeResponse, error) {
+	path := versioned(registrantChangePath(accountID, changeID))
+	changeResponse := &registrantChangeResponse{}
+
+	body := &optionalJSON{}
+	resp, err := s.client.delete(path, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := body.decode(changeResponse); err != nil {
+		return nil, err
+	}
+
+	changeResponse.HttpResponse = resp
+	return changeResponse, nil
+}
+
+// RegistrantChangeResult represents the progress of the registrant change of a domain.
+type RegistrantChangeResult struct {
+	Domain string
+
+	// The requirements of the change, once checked.
+	Check *RegistrantChangeCheck
+
+	// The change, once started. Change.Pending reports whether
+	// the change is waiting for the confirmation of the registrant.
+	Change *RegistrantChange
+
+	// The error returned while processing the domain, if any.
+	Err error
+}
+
+// RegistrantChangeOptions specifies the optional parameters you can provide
+// to customize ChangeDomainsRegistrant.
+type RegistrantChangeOptions struct {
+	// The extended attributes to send with the changes. Each change is sent
+	// with the attributes defined by the TLD of the domain only.
+	ExtendedAttributes map[string]string
+
+	// Progress is called with the result of each domain as soon as it is processed.
+	Progress func(result RegistrantChangeResult)
+}
+
+// ChangeDomainsRegistrant moves the domains to a registrant contact.
+// When the contact has no ID, it is created first.
+//
+// For each domain, the requirements of the change are checked, the extended
+// attributes are validated against the definitions of the TLD, and the change
+// is started. The results are returned in the same order as the domains,
+// and the returned error is the first error encountered, or the context error
+// if the operation was cancelled.
+func ChangeDomainsRegistrant(ctx context.Context, c *Client, accountID string, contact Contact, domainNames []string, options *RegistrantChangeOptions) ([]RegistrantChangeResult, error) {
+	if options == nil {
+		options = &RegistrantChangeOptions{}
+	}
+
+	if contact.ID == 0 {
+		contactResponse, err := c.Contacts.CreateContact(accountID, contact)
+		if err != nil {
+			return nil, fmt.Errorf("creating contact: %v", err)
+		}
+		contact = *contactResponse.Data
+	}
+
+	results := make([]RegistrantChangeResult, len(domainNames))
+	var firstErr error
+	for i, domainName := range domainNames {
+		results[i].Domain = domainName
+
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			firstErr = err
+			continue
+		}
+
+		results[i].Err = changeDomainRegistrant(c, accountID, contact.ID, options.ExtendedAttributes, &results[i])
+		if firstErr == nil && results[i].Err != nil {
+			firstErr = results[i].Err
+		}
+		if options.Progress != nil {
+			options.Progress(results[i])
+		}
+	}
+
+	return results, firstErr
+}
+
+func changeDomainRegistrant(c *Client, accountID string, contactID int64, attributes map[string]string, result *RegistrantChangeResult) error {
+	domainResponse, err := c.Domains.GetDomain(accountID, result.Domain)
+	if err != nil {
+		return err
+	}
+	domainID := domainResponse.Data.ID
+
+	checkResponse, err := c.Registrar.CheckRegistrantChange(accountID, &RegistrantChangeCheckRequest{DomainID: domainID, ContactID: contactID})
+	if err != nil {
+		return err
+	}
+	result.Check = checkResponse.Data
+
+	// Only send the attributes the TLD knows about, so that a single set of
+	// attributes can be used for domains with different TLDs.
+	var domainAttributes map[string]string
+	for _, definition := range result.Check.ExtendedAttributes {
+		if value, ok := attributes[definition.Name]; ok {
+			if domainAttributes == nil {
+				domainAttributes = map[string]string{}
+			}
+			domainAttributes[definition.Name] = value
+		}
+	}
+	if err := ValidateExtendedAttributes(result.Check.ExtendedAttributes, domainAttributes); err != nil {
+		return err
+	}
+
+	changeResponse, err := c.Registrar.CreateRegistrantChange(accountID, &RegistrantChangeRequest{
+		DomainID:           domainID,
+		ContactID:          contactID,
+		ExtendedAttributes: domainAttributes,
+	})
+	if err != nil {
+		return err
+	}
+	result.Change = changeResponse.Data
+
+	return nil
+}
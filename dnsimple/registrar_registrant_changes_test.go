@@ -0,0 +1,196 @@
+package dnsimple
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestRegistrarService_ListRegistrantChanges(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/registrar/registrant_changes", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/listRegistrantChanges/success.http")
+
+		testMethod(t, r, "GET")
+		testHeaders(t, r)
+		testQuery(t, r, url.Values{"domain_id": []string{"101"}, "state": []string{"new"}})
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	changesResponse, err := client.Registrar.ListRegistrantChanges("1010", &RegistrantChangeListOptions{DomainID: 101, State: "new"})
+	if err != nil {
+		t.Fatalf("Registrar.ListRegistrantChanges() returned error: %v", err)
+	}
+
+	changes := changesResponse.Data
+	if want, got := 1, len(changes); want != got {
+		t.Fatalf("Registrar.ListRegistrantChanges() expected to return %v changes, got %v", want, got)
+	}
+	if want, got := int64(101), changes[0].ID; want != got {
+		t.Errorf("Registrar.ListRegistrantChanges() returned ID expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestRegistrarService_CheckRegistrantChange(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/registrar/registrant_changes/check", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/checkRegistrantChange/success.http")
+
+		testMethod(t, r, "POST")
+		testHeaders(t, r)
+		testRequestJSON(t, r, map[string]interface{}{"domain_id": float64(101), "contact_id": float64(101)})
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	checkResponse, err := client.Registrar.CheckRegistrantChange("1010", &RegistrantChangeCheckRequest{DomainID: 101, ContactID: 101})
+	if err != nil {
+		t.Fatalf("Registrar.CheckRegistrantChange() returned error: %v", err)
+	}
+
+	check := checkResponse.Data
+	if want, got := true, check.RegistryOwnerChange; want != got {
+		t.Errorf("Registrar.CheckRegistrantChange() returned RegistryOwnerChange expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := 0, len(check.ExtendedAttributes); want != got {
+		t.Errorf("Registrar.CheckRegistrantChange() expected to return %v extended attributes, got %v", want, got)
+	}
+}
+
+func TestRegistrarService_CreateRegistrantChange(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/registrar/registrant_changes", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/createRegistrantChange/success.http")
+
+		testMethod(t, r, "POST")
+		testHeaders(t, r)
+		testRequestJSON(t, r, map[string]interface{}{"domain_id": float64(101), "contact_id": float64(101), "extended_attributes": map[string]interface{}{"x-fi-registrant-idnumber": "1234"}})
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	request := &RegistrantChangeRequest{DomainID: 101, ContactID: 101, ExtendedAttributes: map[string]string{"x-fi-registrant-idnumber": "1234"}}
+	changeResponse, err := client.Registrar.CreateRegistrantChange("1010", request)
+	if err != nil {
+		t.Fatalf("Registrar.CreateRegistrantChange() returned error: %v", err)
+	}
+
+	if want, got := "new", changeResponse.Data.State; want != got {
+		t.Errorf("Registrar.CreateRegistrantChange() returned State expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestRegistrarService_GetRegistrantChange(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/registrar/registrant_changes/101", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/getRegistrantChange/success.http")
+
+		testMethod(t, r, "GET")
+		testHeaders(t, r)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	changeResponse, err := client.Registrar.GetRegistrantChange("1010", 101)
+	if err != nil {
+		t.Fatalf("Registrar.GetRegistrantChange() returned error: %v", err)
+	}
+
+	change := changeResponse.Data
+	if want, got := "cancelled", change.State; want != got {
+		t.Errorf("Registrar.GetRegistrantChange() returned State expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := int64(101), change.ContactID; want != got {
+		t.Errorf("Registrar.GetRegistrantChange() returned ContactID expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestRegistrarService_CancelRegistrantChange(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	fixture := "/api/deleteRegistrantChange/success.http"
+	mux.HandleFunc("/v2/1010/registrar/registrant_changes/101", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, fixture)
+
+		testMethod(t, r, "DELETE")
+		testHeaders(t, r)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	changeResponse, err := client.Registrar.CancelRegistrantChange("1010", 101)
+	if err != nil {
+		t.Fatalf("Registrar.CancelRegistrantChange() returned error: %v", err)
+	}
+	if changeResponse.Data != nil {
+		t.Errorf("Registrar.CancelRegistrantChange() expected to return no change, got %+v", changeResponse.Data)
+	}
+
+	fixture = "/api/deleteRegistrantChange/success-async.http"
+	changeResponse, err = client.Registrar.CancelRegistrantChange("1010", 101)
+	if err != nil {
+		t.Fatalf("Registrar.CancelRegistrantChange() returned error: %v", err)
+	}
+	if want, got := "cancelling", changeResponse.Data.State; want != got {
+		t.Errorf("Registrar.CancelRegistrantChange() returned State expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestChangeDomainsRegistrant(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	serveFixture(t, "/v2/1010/domains/example-alpha.com", "/api/getDomain/success.http")
+	serveFixture(t, "/v2/1010/domains/example.invalid", "/api/notfound-domain.http")
+	serveFixture(t, "/v2/1010/registrar/registrant_changes/check", "/api/checkRegistrantChange/success.http")
+	mux.HandleFunc("/v2/1010/registrar/registrant_changes", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/createRegistrantChange/success.http")
+
+		testMethod(t, r, "POST")
+		testRequestJSON(t, r, map[string]interface{}{"domain_id": float64(1), "contact_id": float64(101)})
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	var progress []string
+	options := &RegistrantChangeOptions{
+		ExtendedAttributes: map[string]string{"us_nexus": "C11"},
+		Progress:           func(result RegistrantChangeResult) { progress = append(progress, result.Domain) },
+	}
+
+	results, err := ChangeDomainsRegistrant(context.Background(), client, "1010", Contact{ID: 101}, []string{"example-alpha.com", "example.invalid"}, options)
+	if err == nil {
+		t.Errorf("ChangeDomainsRegistrant() expected to return an error for the missing domain")
+	}
+
+	if want, got := 2, len(progress); want != got {
+		t.Errorf("ChangeDomainsRegistrant() expected to report progress %v times, got %v", want, got)
+	}
+	if results[0].Err != nil {
+		t.Fatalf("ChangeDomainsRegistrant() returned error for %v: %v", results[0].Domain, results[0].Err)
+	}
+	if want, got := "new", results[0].Change.State; want != got {
+		t.Errorf("ChangeDomainsRegistrant() returned State expected to be `%v`, got `%v`", want, got)
+	}
+	if results[1].Err == nil {
+		t.Errorf("ChangeDomainsRegistrant() expected to return an error for %v", results[1].Domain)
+	}
+}
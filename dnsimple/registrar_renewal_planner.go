@@ -0,0 +1,211 @@
+package dnsimple
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// defaultRenewalWindow is the number of days before the expiration
+// a domain is planned for renewal when no window is specified.
+const defaultRenewalWindow = 30
+
+// RenewalPlanOptions specifies the optional parameters you can provide
+// to customize PlanRenewals.
+type RenewalPlanOptions struct {
+	// The number of days before the expiration a domain is planned for renewal.
+	// Defaults to 30. Expired domains are always planned.
+	Window int
+
+	// The number of years to renew the domains for. Defaults to 1,
+	// and is raised to the minimum registration period of the TLD when needed.
+	Period int
+
+	// Set to true to plan the renewal of the domains with auto-renewal enabled.
+	// By default they are left to the auto-renewal.
+	IncludeAutoRenew bool
+
+	// The time the plan is computed at. Defaults to the current time.
+	Now time.Time
+}
+
+// RenewalPlanItem represents the planned renewal of a domain.
+type RenewalPlanItem struct {
+	Domain    string
+	ExpiresOn time.Time
+	AutoRenew bool
+
+	// The number of years the domain is renewed for.
+	Period int
+
+	// The premium renewal price, only for premium domains.
+	// It's sent along with the renewal request as a confirmation.
+	PremiumPrice string
+
+	// The price of the renewal for the whole period.
+	Cost float64
+}
+
+// RenewalPlanSkip represents a domain in the window that can't be renewed.
+type RenewalPlanSkip struct {
+	Domain string
+	Reason string
+}
+
+// RenewalPlan represents the domains to renew, and the total cost of the renewals.
+type RenewalPlan struct {
+	Items   []RenewalPlanItem
+	Skipped []RenewalPlanSkip
+
+	TotalCost float64
+}
+
+// PlanRenewals lists the registered domains of an account that expire within
+// the window, along with the domains that already expired, and computes
+// the period and the cost of their renewal.
+//
+// Domains in a TLD that doesn't allow renewals are reported as skipped.
+// Domains with auto-renewal enabled are ignored, unless IncludeAutoRenew is set.
+func PlanRenewals(c *Client, accountID string, options *RenewalPlanOptions) (*RenewalPlan, error) {
+	if options == nil {
+		options = &RenewalPlanOptions{}
+	}
+	window, period, now := options.Window, options.Period, options.Now
+	if window <= 0 {
+		window = defaultRenewalWindow
+	}
+	if period <= 0 {
+		period = 1
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	domains, err := listAllDomains(c, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("listing domains: %v", err)
+	}
+
+	plan := &RenewalPlan{}
+	tlds := map[string]*Tld{}
+	for _, domain := range domains {
+		if (domain.State != "registered" && domain.State != "expired") || (domain.AutoRenew && !options.IncludeAutoRenew) {
+			continue
+		}
+
+		expiresOn, err := domain.ExpiresOnTime()
+		if err != nil {
+			return nil, fmt.Errorf("domain %v: %v", domain.Name, err)
+		}
+		if expiresOn.IsZero() || expiresOn.After(now.AddDate(0, 0, window)) {
+			continue
+		}
+
+		tldName := domainTld(domain.Name)
+		tld, ok := tlds[tldName]
+		if !ok {
+			tldResponse, err := c.Tlds.GetTld(tldName)
+			if err != nil {
+				return nil, fmt.Errorf("getting TLD %v: %v", tldName, err)
+			}
+			tld = tldResponse.Data
+			tlds[tldName] = tld
+		}
+		if !tld.RenewalEnabled {
+			plan.Skipped = append(plan.Skipped, RenewalPlanSkip{Domain: domain.Name, Reason: fmt.Sprintf("renewals are not enabled for .%v", tldName)})
+			continue
+		}
+
+		item := RenewalPlanItem{Domain: domain.Name, ExpiresOn: expiresOn, AutoRenew: domain.AutoRenew, Period: period}
+		if item.Period < tld.MinimumRegistration {
+			item.Period = tld.MinimumRegistration
+		}
+
+		if err := priceRenewal(c, accountID, &item); err != nil {
+			return nil, fmt.Errorf("pricing the renewal of %v: %v", domain.Name, err)
+		}
+
+		plan.Items = append(plan.Items, item)
+		plan.TotalCost += item.Cost
+	}
+
+	sort.Stable(renewalPlanItemsByDate(plan.Items))
+
+	return plan, nil
+}
+
+type renewalPlanItemsByDate []RenewalPlanItem
+
+func (s renewalPlanItemsByDate) Len() int           { return len(s) }
+func (s renewalPlanItemsByDate) Less(i, j int) bool { return s[i].ExpiresOn.Before(s[j].ExpiresOn) }
+func (s renewalPlanItemsByDate) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// priceRenewal sets the cost of the renewal, and the premium price for premium domains.
+func priceRenewal(c *Client, accountID string, item *RenewalPlanItem) error {
+	pricesResponse, err := c.Registrar.GetDomainPrices(accountID, item.Domain)
+	if err != nil {
+		return err
+	}
+	yearly := pricesResponse.Data.RenewalPrice
+
+	if pricesResponse.Data.Premium {
+		premiumResponse, err := c.Registrar.GetDomainPremiumPrice(accountID, item.Domain, &DomainPremiumPriceOptions{Action: "renewal"})
+		if err != nil {
+			return err
+		}
+		item.PremiumPrice = premiumResponse.Data.PremiumPrice
+
+		yearly, err = strconv.ParseFloat(item.PremiumPrice, 64)
+		if err != nil {
+			return fmt.Errorf("invalid premium price %v: %v", item.PremiumPrice, err)
+		}
+	}
+
+	item.Cost = yearly * float64(item.Period)
+	return nil
+}
+
+// RenewalResult represents the outcome of the renewal of a domain.
+type RenewalResult struct {
+	Domain string
+
+	// The renewal returned by the API, if any.
+	Renewal *DomainRenewal
+
+	// The error returned for this domain, if any.
+	Err error
+}
+
+// Execute renews the domains of the plan, one at a time.
+//
+// The results are returned in the same order as the plan items. The returned
+// error is the first error encountered, or the context error if the execution
+// was cancelled, in which case the remaining domains are not renewed.
+func (p *RenewalPlan) Execute(ctx context.Context, c *Client, accountID string) ([]RenewalResult, error) {
+	results := make([]RenewalResult, len(p.Items))
+	var firstErr error
+
+	for i, item := range p.Items {
+		results[i].Domain = item.Domain
+
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			firstErr = err
+			continue
+		}
+
+		renewalResponse, err := c.Registrar.RenewDomain(accountID, item.Domain, &DomainRenewRequest{Period: item.Period, PremiumPrice: item.PremiumPrice})
+		if err != nil {
+			results[i].Err = err
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		results[i].Renewal = renewalResponse.Data
+	}
+
+	return results, firstErr
+}
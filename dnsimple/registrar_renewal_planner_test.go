@@ -0,0 +1,135 @@
+package dnsimple
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPlanRenewals(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	serveFixture(t, "/v2/1010/domains", "/api/listDomains/success.http")
+	serveFixture(t, "/v2/tlds/com", "/api/getTld/success.http")
+	serveFixture(t, "/v2/1010/registrar/domains/example-beta.com/prices", "/api/getDomainPrices/success.http")
+	mux.HandleFunc("/v2/1010/registrar/domains/example-beta.com/premium_price", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/getDomainPremiumPrice/success.http")
+
+		testQuery(t, r, map[string][]string{"action": {"renewal"}})
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	now := time.Date(2015, 11, 20, 0, 0, 0, 0, time.UTC)
+
+	plan, err := PlanRenewals(client, "1010", &RenewalPlanOptions{Now: now, Period: 2})
+	if err != nil {
+		t.Fatalf("PlanRenewals() returned error: %v", err)
+	}
+
+	if want, got := 1, len(plan.Items); want != got {
+		t.Fatalf("PlanRenewals() expected to plan %v renewals, got %v", want, got)
+	}
+	item := plan.Items[0]
+	if want, got := "example-beta.com", item.Domain; want != got {
+		t.Errorf("PlanRenewals() Domain expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := "109.00", item.PremiumPrice; want != got {
+		t.Errorf("PlanRenewals() PremiumPrice expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := 218.0, plan.TotalCost; want != got {
+		t.Errorf("PlanRenewals() TotalCost expected to be `%v`, got `%v`", want, got)
+	}
+
+	plan, err = PlanRenewals(client, "1010", &RenewalPlanOptions{Now: now, Window: 10})
+	if err != nil {
+		t.Fatalf("PlanRenewals() returned error: %v", err)
+	}
+	if want, got := 0, len(plan.Items); want != got {
+		t.Errorf("PlanRenewals() expected to plan %v renewals outside of the window, got %v", want, got)
+	}
+}
+
+func TestPlanRenewals_ExpiredDomain(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	serveFixture(t, "/v2/1010/domains", "/api/listDomains/expired.http")
+	serveFixture(t, "/v2/tlds/com", "/api/getTld/success.http")
+	serveFixture(t, "/v2/1010/registrar/domains/example-gamma.com/prices", "/api/getDomainPrices/success.http")
+	serveFixture(t, "/v2/1010/registrar/domains/example-gamma.com/premium_price", "/api/getDomainPremiumPrice/success.http")
+
+	plan, err := PlanRenewals(client, "1010", &RenewalPlanOptions{Now: time.Date(2015, 11, 20, 0, 0, 0, 0, time.UTC), Window: 10})
+	if err != nil {
+		t.Fatalf("PlanRenewals() returned error: %v", err)
+	}
+
+	if want, got := 1, len(plan.Items); want != got {
+		t.Fatalf("PlanRenewals() expected to plan %v renewals, got %v", want, got)
+	}
+	if want, got := "example-gamma.com", plan.Items[0].Domain; want != got {
+		t.Errorf("PlanRenewals() Domain expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestPlanRenewals_RenewalDisabled(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	serveFixture(t, "/v2/1010/domains", "/api/listDomains/success.http")
+	mux.HandleFunc("/v2/tlds/com", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"data":{"tld":"com","minimum_registration":1,"renewal_enabled":false}}`)
+	})
+
+	plan, err := PlanRenewals(client, "1010", &RenewalPlanOptions{Now: time.Date(2015, 11, 20, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("PlanRenewals() returned error: %v", err)
+	}
+
+	if want, got := 0, len(plan.Items); want != got {
+		t.Errorf("PlanRenewals() expected to plan %v renewals, got %v", want, got)
+	}
+	if want, got := 1, len(plan.Skipped); want != got {
+		t.Fatalf("PlanRenewals() expected to skip %v domains, got %v", want, got)
+	}
+	if want, got := "example-beta.com", plan.Skipped[0].Domain; want != got {
+		t.Errorf("PlanRenewals() skipped Domain expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestRenewalPlan_Execute(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/renewals", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/renewDomain/success.http")
+
+		testMethod(t, r, "POST")
+		testRequestJSON(t, r, map[string]interface{}{"period": float64(3), "premium_price": "109.00"})
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+	serveFixture(t, "/v2/1010/registrar/domains/example.invalid/renewals", "/api/notfound-domain.http")
+
+	plan := &RenewalPlan{Items: []RenewalPlanItem{
+		{Domain: "example.com", Period: 3, PremiumPrice: "109.00"},
+		{Domain: "example.invalid", Period: 1},
+	}}
+
+	results, err := plan.Execute(context.Background(), client, "1010")
+	if err == nil {
+		t.Errorf("Execute() expected to return an error")
+	}
+
+	if results[0].Err != nil || results[0].Renewal == nil {
+		t.Errorf("Execute() expected to renew %v, got %+v", results[0].Domain, results[0])
+	}
+	if results[1].Err == nil {
+		t.Errorf("Execute() expected to return an error for %v", results[1].Domain)
+	}
+}
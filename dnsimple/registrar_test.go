@@ -81,6 +81,34 @@ func TestRegistrarService_GetDomainPremiumPrice_WithOptions(t *testing.T) {
 	}
 }
 
+func TestRegistrarService_GetDomainPrices(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/registrar/domains/bingo.pizza/prices", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/getDomainPrices/success.http")
+
+		testMethod(t, r, "GET")
+		testHeaders(t, r)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	priceResponse, err := client.Registrar.GetDomainPrices("1010", "bingo.pizza")
+	if err != nil {
+		t.Fatalf("Registrar.GetDomainPrices() returned error: %v", err)
+	}
+
+	price := priceResponse.Data
+	if want, got := true, price.Premium; want != got {
+		t.Errorf("Registrar.GetDomainPrices() returned Premium expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := 20.0, price.RenewalPrice; want != got {
+		t.Errorf("Registrar.GetDomainPrices() returned RenewalPrice expected to be `%v`, got `%v`", want, got)
+	}
+}
+
 func TestRegistrarService_RegisterDomain(t *testing.T) {
 	setupMockServer()
 	defer teardownMockServer()
@@ -114,6 +142,34 @@ func TestRegistrarService_RegisterDomain(t *testing.T) {
 	}
 }
 
+func TestRegistrarService_GetDomainRegistration(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/registrations/1", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/getDomainRegistration/success.http")
+
+		testMethod(t, r, "GET")
+		testHeaders(t, r)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	registrationResponse, err := client.Registrar.GetDomainRegistration("1010", "example.com", 1)
+	if err != nil {
+		t.Fatalf("Registrar.GetDomainRegistration() returned error: %v", err)
+	}
+
+	registration := registrationResponse.Data
+	if want, got := 1, registration.ID; want != got {
+		t.Fatalf("Registrar.GetDomainRegistration() returned ID expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := "registering", registration.State; want != got {
+		t.Fatalf("Registrar.GetDomainRegistration() returned State expected to be `%v`, got `%v`", want, got)
+	}
+}
+
 func TestRegistrarService_TransferDomain(t *testing.T) {
 	setupMockServer()
 	defer teardownMockServer()
@@ -147,6 +203,82 @@ func TestRegistrarService_TransferDomain(t *testing.T) {
 	}
 }
 
+func TestRegistrarService_TransferDomain_ExtendedAttributes(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/transfers", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/transferDomain/success.http")
+
+		testMethod(t, r, "POST")
+		testHeaders(t, r)
+
+		want := map[string]interface{}{"registrant_id": float64(2), "auth_code": "x1y2z3", "extended_attributes": map[string]interface{}{"us_nexus": "C11"}}
+		testRequestJSON(t, r, want)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	transferRequest := &DomainTransferRequest{RegistrantID: 2, AuthCode: "x1y2z3", ExtendedAttributes: map[string]string{"us_nexus": "C11"}}
+
+	if _, err := client.Registrar.TransferDomain("1010", "example.com", transferRequest); err != nil {
+		t.Fatalf("Registrar.TransferDomain() returned error: %v", err)
+	}
+}
+
+func TestRegistrarService_GetDomainTransfer(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/transfers/1", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/getDomainTransfer/success.http")
+
+		testMethod(t, r, "GET")
+		testHeaders(t, r)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	transferResponse, err := client.Registrar.GetDomainTransfer("1010", "example.com", 1)
+	if err != nil {
+		t.Fatalf("Registrar.GetDomainTransfer() returned error: %v", err)
+	}
+
+	transfer := transferResponse.Data
+	if want, got := "failed", transfer.State; want != got {
+		t.Fatalf("Registrar.GetDomainTransfer() returned State expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := "The auth code is invalid.", transfer.StatusDescription; want != got {
+		t.Fatalf("Registrar.GetDomainTransfer() returned StatusDescription expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestRegistrarService_CancelDomainTransfer(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/transfers/1", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/cancelDomainTransfer/success.http")
+
+		testMethod(t, r, "DELETE")
+		testHeaders(t, r)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	transferResponse, err := client.Registrar.CancelDomainTransfer("1010", "example.com", 1)
+	if err != nil {
+		t.Fatalf("Registrar.CancelDomainTransfer() returned error: %v", err)
+	}
+
+	if want, got := 1, transferResponse.Data.ID; want != got {
+		t.Fatalf("Registrar.CancelDomainTransfer() returned ID expected to be `%v`, got `%v`", want, got)
+	}
+}
+
 func TestRegistrarService_TransferDomainOut(t *testing.T) {
 	setupMockServer()
 	defer teardownMockServer()
@@ -161,10 +293,14 @@ func TestRegistrarService_TransferDomainOut(t *testing.T) {
 		io.Copy(w, httpResponse.Body)
 	})
 
-	_, err := client.Registrar.TransferDomainOut("1010", "example.com")
+	transferResponse, err := client.Registrar.TransferDomainOut("1010", "example.com")
 	if err != nil {
 		t.Fatalf("Registrar.TransferOut() returned error: %v", err)
 	}
+
+	if transferResponse.Data != nil {
+		t.Errorf("Registrar.TransferOut() returned Data expected to be nil, got `%v`", transferResponse.Data)
+	}
 }
 
 func TestRegistrarService_RenewDomain(t *testing.T) {
@@ -0,0 +1,64 @@
+package dnsimple
+
+import (
+	"fmt"
+)
+
+// TransferLock represents the transfer lock state of a domain.
+type TransferLock struct {
+	Enabled bool `json:"enabled"`
+}
+
+// transferLockResponse represents a response from an API method that returns a TransferLock struct.
+type transferLockResponse struct {
+	Response
+	Data *TransferLock `json:"data"`
+}
+
+// GetDomainTransferLock gets the transfer lock state of the domain.
+//
+// See https://developer.dnsimple.com/v2/registrar/#getDomainTransferLock
+func (s *RegistrarService) GetDomainTransferLock(accountID string, domainName string) (*transferLockResponse, error) {
+	path := versioned(fmt.Sprintf("/%v/registrar/domains/%v/transfer_lock", accountID, domainName))
+	lockResponse := &transferLockResponse{}
+
+	resp, err := s.client.get(path, lockResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	lockResponse.HttpResponse = resp
+	return lockResponse, nil
+}
+
+// EnableDomainTransferLock locks the domain, preventing transfers to another registrar.
+//
+// See https://developer.dnsimple.com/v2/registrar/#enableDomainTransferLock
+func (s *RegistrarService) EnableDomainTransferLock(accountID string, domainName string) (*transferLockResponse, error) {
+	path := versioned(fmt.Sprintf("/%v/registrar/domains/%v/transfer_lock", accountID, domainName))
+	lockResponse := &transferLockResponse{}
+
+	resp, err := s.client.post(path, nil, lockResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	lockResponse.HttpResponse = resp
+	return lockResponse, nil
+}
+
+// DisableDomainTransferLock unlocks the domain, allowing transfers to another registrar.
+//
+// See https://developer.dnsimple.com/v2/registrar/#disableDomainTransferLock
+func (s *RegistrarService) DisableDomainTransferLock(accountID string, domainName string) (*transferLockResponse, error) {
+	path := versioned(fmt.Sprintf("/%v/registrar/domains/%v/transfer_lock", accountID, domainName))
+	lockResponse := &transferLockResponse{}
+
+	resp, err := s.client.delete(path, nil, lockResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	lockResponse.HttpResponse = resp
+	return lockResponse, nil
+}
@@ -0,0 +1,79 @@
+package dnsimple
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestRegistrarService_GetDomainTransferLock(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/transfer_lock", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/getDomainTransferLock/success.http")
+
+		testMethod(t, r, "GET")
+		testHeaders(t, r)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	lockResponse, err := client.Registrar.GetDomainTransferLock("1010", "example.com")
+	if err != nil {
+		t.Fatalf("Registrar.GetDomainTransferLock() returned error: %v", err)
+	}
+
+	if want, got := true, lockResponse.Data.Enabled; want != got {
+		t.Errorf("Registrar.GetDomainTransferLock() returned Enabled expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestRegistrarService_EnableDomainTransferLock(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/transfer_lock", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/enableDomainTransferLock/success.http")
+
+		testMethod(t, r, "POST")
+		testHeaders(t, r)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	lockResponse, err := client.Registrar.EnableDomainTransferLock("1010", "example.com")
+	if err != nil {
+		t.Fatalf("Registrar.EnableDomainTransferLock() returned error: %v", err)
+	}
+
+	if want, got := true, lockResponse.Data.Enabled; want != got {
+		t.Errorf("Registrar.EnableDomainTransferLock() returned Enabled expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestRegistrarService_DisableDomainTransferLock(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/transfer_lock", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/disableDomainTransferLock/success.http")
+
+		testMethod(t, r, "DELETE")
+		testHeaders(t, r)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	lockResponse, err := client.Registrar.DisableDomainTransferLock("1010", "example.com")
+	if err != nil {
+		t.Fatalf("Registrar.DisableDomainTransferLock() returned error: %v", err)
+	}
+
+	if want, got := false, lockResponse.Data.Enabled; want != got {
+		t.Errorf("Registrar.DisableDomainTransferLock() returned Enabled expected to be `%v`, got `%v`", want, got)
+	}
+}
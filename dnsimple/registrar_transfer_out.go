@@ -0,0 +1,59 @@
+package dnsimple
+
+import (
+	"fmt"
+)
+
+// TransferOutPreparation represents a domain prepared for the transfer to another registrar.
+type TransferOutPreparation struct {
+	Domain *Domain
+
+	// Set to true when the domain was locked, and has been unlocked.
+	Unlocked bool
+
+	// Set to true when the authorization code has been sent to the registrant.
+	// The API doesn't expose the code, the registrant receives it by email.
+	AuthCodeSent bool
+}
+
+// PrepareDomainTransferOut prepares a registered domain for the transfer
+// to another registrar: the transfer lock is disabled, and the authorization
+// code is sent to the registrant.
+//
+// If the authorization fails after the domain has been unlocked,
+// the transfer lock is enabled again.
+func PrepareDomainTransferOut(c *Client, accountID string, domainName string) (*TransferOutPreparation, error) {
+	domainResponse, err := c.Domains.GetDomain(accountID, domainName)
+	if err != nil {
+		return nil, fmt.Errorf("getting domain: %v", err)
+	}
+	preparation := &TransferOutPreparation{Domain: domainResponse.Data}
+	if state := preparation.Domain.State; state != "registered" {
+		return preparation, fmt.Errorf("domain %v can't be transferred out in state %v", domainName, state)
+	}
+
+	lockResponse, err := c.Registrar.GetDomainTransferLock(accountID, domainName)
+	if err != nil {
+		return preparation, fmt.Errorf("getting transfer lock: %v", err)
+	}
+	if lockResponse.Data.Enabled {
+		if _, err := c.Registrar.DisableDomainTransferLock(accountID, domainName); err != nil {
+			return preparation, fmt.Errorf("disabling transfer lock: %v", err)
+		}
+		preparation.Unlocked = true
+	}
+
+	if _, err := c.Registrar.TransferDomainOut(accountID, domainName); err != nil {
+		err = fmt.Errorf("authorizing transfer out: %v", err)
+		if preparation.Unlocked {
+			if _, lockErr := c.Registrar.EnableDomainTransferLock(accountID, domainName); lockErr != nil {
+				return preparation, fmt.Errorf("%v (enabling transfer lock again: %v)", err, lockErr)
+			}
+			preparation.Unlocked = false
+		}
+		return preparation, err
+	}
+	preparation.AuthCodeSent = true
+
+	return preparation, nil
+}
@@ -0,0 +1,70 @@
+package dnsimple
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func setupTransferOutFixtures(t *testing.T, authorizeFixture string) *[]string {
+	var methods []string
+
+	mux.HandleFunc("/v2/1010/domains/example.com", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"data":{"id":1,"account_id":1010,"name":"example.com","state":"registered"}}`)
+	})
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/transfer_lock", func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+
+		fixture := map[string]string{
+			"GET":    "/api/getDomainTransferLock/success.http",
+			"POST":   "/api/enableDomainTransferLock/success.http",
+			"DELETE": "/api/disableDomainTransferLock/success.http",
+		}[r.Method]
+		httpResponse := httpResponseFixture(t, fixture)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+	serveFixture(t, "/v2/1010/registrar/domains/example.com/authorize_transfer_out", authorizeFixture)
+
+	return &methods
+}
+
+func TestPrepareDomainTransferOut(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	methods := setupTransferOutFixtures(t, "/api/authorizeDomainTransferOut/success.http")
+
+	preparation, err := PrepareDomainTransferOut(client, "1010", "example.com")
+	if err != nil {
+		t.Fatalf("PrepareDomainTransferOut() returned error: %v", err)
+	}
+
+	if !preparation.Unlocked || !preparation.AuthCodeSent {
+		t.Errorf("PrepareDomainTransferOut() expected the domain to be unlocked and the code sent, got %+v", preparation)
+	}
+	if want, got := "GET,DELETE", strings.Join(*methods, ","); want != got {
+		t.Errorf("PrepareDomainTransferOut() transfer lock requests expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestPrepareDomainTransferOut_Rollback(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	methods := setupTransferOutFixtures(t, "/api/validation-error.http")
+
+	preparation, err := PrepareDomainTransferOut(client, "1010", "example.com")
+	if err == nil {
+		t.Fatalf("PrepareDomainTransferOut() expected to return an error")
+	}
+
+	if preparation.Unlocked || preparation.AuthCodeSent {
+		t.Errorf("PrepareDomainTransferOut() expected the domain to be locked again, got %+v", preparation)
+	}
+	if want, got := "GET,DELETE,POST", strings.Join(*methods, ","); want != got {
+		t.Errorf("PrepareDomainTransferOut() transfer lock requests expected to be `%v`, got `%v`", want, got)
+	}
+}
@@ -0,0 +1,444 @@
+package dnsimple
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTransferMinInterval = 5 * time.Minute
+	defaultTransferMaxInterval = 6 * time.Hour
+)
+
+// TrackedTransfer represents a domain transfer followed by a TransferTracker.
+type TrackedTransfer struct {
+	AccountID  string         `json:"account_id"`
+	DomainName string         `json:"domain_name"`
+	Transfer   DomainTransfer `json:"transfer"`
+
+	// The time of the next check of the transfer state, and the current
+	// interval between two checks. The interval doubles every time
+	// the state is found unchanged.
+	NextCheck time.Time     `json:"next_check"`
+	Interval  time.Duration `json:"interval"`
+}
+
+func (t TrackedTransfer) key() string {
+	return t.AccountID + "/" + strings.ToLower(t.DomainName)
+}
+
+// TransferStateChange represents a change of state of a tracked transfer.
+type TransferStateChange struct {
+	AccountID     string
+	DomainName    string
+	PreviousState string
+
+	// The transfer in its new state. When the transfer failed,
+	// Transfer.StatusDescription reports the reason of the failure.
+	Transfer DomainTransfer
+}
+
+// Done returns true if the transfer reached a final state.
+func (c TransferStateChange) Done() bool {
+	return transferDone(c.Transfer.State)
+}
+
+func transferDone(state string) bool {
+	return state == "transferred" || state == "cancelled" || state == "failed"
+}
+
+// TransferStore persists the transfers followed by a TransferTracker,
+// so that they can be followed across restarts.
+type TransferStore interface {
+	// Load returns all the stored transfers.
+	Load() ([]TrackedTransfer, error)
+
+	// Save stores a transfer, replacing the one with the same account and domain name.
+	Save(transfer TrackedTransfer) error
+
+	// Delete removes a transfer.
+	Delete(transfer TrackedTransfer) error
+}
+
+// MemoryTransferStore is a TransferStore that keeps the transfers in memory.
+type MemoryTransferStore struct {
+	mu        sync.Mutex
+	transfers map[string]TrackedTransfer
+}
+
+// NewMemoryTransferStore returns an empty MemoryTransferStore.
+func NewMemoryTransferStore() *MemoryTransferStore {
+	return &MemoryTransferStore{transfers: map[string]TrackedTransfer{}}
+}
+
+// Load implements TransferStore.
+func (s *MemoryTransferStore) Load() ([]TrackedTransfer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transfers := make([]TrackedTransfer, 0, len(s.transfers))
+	for _, transfer := range s.transfers {
+		transfers = append(transfers, transfer)
+	}
+	sort.Sort(trackedTransfersByKey(transfers))
+	return transfers, nil
+}
+
+type trackedTransfersByKey []TrackedTransfer
+
+func (s trackedTransfersByKey) Len() int           { return len(s) }
+func (s trackedTransfersByKey) Less(i, j int) bool { return s[i].key() < s[j].key() }
+func (s trackedTransfersByKey) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// Save implements TransferStore.
+func (s *MemoryTransferStore) Save(transfer TrackedTransfer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transfers[transfer.key()] = transfer
+	return nil
+}
+
+// Delete implements TransferStore.
+func (s *MemoryTransferStore) Delete(transfer TrackedTransfer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.transfers, transfer.key())
+	return nil
+}
+
+// FileTransferStore is a TransferStore that keeps the transfers in a JSON file.
+type FileTransferStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// Load implements TransferStore.
+func (s *FileTransferStore) Load() ([]TrackedTransfer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read()
+}
+
+// Save implements TransferStore.
+func (s *FileTransferStore) Save(transfer TrackedTransfer) error {
+	return s.update(func(transfers []TrackedTransfer) []TrackedTransfer {
+		for i := range transfers {
+			if transfers[i].key() == transfer.key() {
+				transfers[i] = transfer
+				return transfers
+			}
+		}
+		return append(transfers, transfer)
+	})
+}
+
+// Delete implements TransferStore.
+func (s *FileTransferStore) Delete(transfer TrackedTransfer) error {
+	return s.update(func(transfers []TrackedTransfer) []TrackedTransfer {
+		kept := transfers[:0]
+		for _, t := range transfers {
+			if t.key() != transfer.key() {
+				kept = append(kept, t)
+			}
+		}
+		return kept
+	})
+}
+
+func (s *FileTransferStore) read() ([]TrackedTransfer, error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var transfers []TrackedTransfer
+	if err := json.Unmarshal(data, &transfers); err != nil {
+		return nil, fmt.Errorf("reading %v: %v", s.Path, err)
+	}
+	return transfers, nil
+}
+
+func (s *FileTransferStore) update(fn func([]TrackedTransfer) []TrackedTransfer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transfers, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(fn(transfers), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so that a crash never leaves a truncated store.
+	tmp := s.Path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
+
+// TransferTracker follows inbound domain transfers until they complete.
+//
+// The state of each transfer is polled with an exponential backoff,
+// and a check can be anticipated when a domain.transfer webhook event
+// is received. See HandleWebhookEvent.
+type TransferTracker struct {
+	client *Client
+	store  TransferStore
+
+	// OnStateChange is called every time the state of a transfer changes.
+	OnStateChange func(change TransferStateChange)
+
+	// The minimum and maximum interval between two checks of a transfer.
+	// Default to 5 minutes and 6 hours.
+	MinInterval time.Duration
+	MaxInterval time.Duration
+
+	wakeup chan struct{}
+
+	// mu serializes the changes to the store, so that a change made
+	// from a webhook never overwrites a change made by a check.
+	mu sync.Mutex
+}
+
+// NewTransferTracker returns a TransferTracker that persists the transfers in the store.
+func NewTransferTracker(c *Client, store TransferStore) *TransferTracker {
+	return &TransferTracker{
+		client:      c,
+		store:       store,
+		MinInterval: defaultTransferMinInterval,
+		MaxInterval: defaultTransferMaxInterval,
+		wakeup:      make(chan struct{}, 1),
+	}
+}
+
+// TransferDomain starts the transfer of a domain and tracks it.
+func (t *TransferTracker) TransferDomain(accountID string, domainName string, request *DomainTransferRequest) (*DomainTransfer, error) {
+	transferResponse, err := t.client.Registrar.TransferDomain(accountID, domainName, request)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer := transferResponse.Data
+	return transfer, t.Track(accountID, domainName, *transfer)
+}
+
+// Track adds a transfer to the tracked transfers. The transfer is checked on the next poll.
+func (t *TransferTracker) Track(accountID string, domainName string, transfer DomainTransfer) error {
+	tracked := TrackedTransfer{AccountID: accountID, DomainName: domainName, Transfer: transfer, Interval: t.MinInterval}
+
+	t.mu.Lock()
+	err := t.store.Save(tracked)
+	t.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	t.wake()
+	return nil
+}
+
+// HandleWebhookEvent correlates a webhook event with the tracked transfers.
+// When the event is a domain.transfer event for a tracked domain,
+// the transfer is checked on the next poll and HandleWebhookEvent returns true.
+//
+// Pass the name and the domain of a webhook.DomainEvent.
+func (t *TransferTracker) HandleWebhookEvent(eventName string, domain *Domain) (bool, error) {
+	if domain == nil || (eventName != "domain.transfer" && !strings.HasPrefix(eventName, "domain.transfer:")) {
+		return false, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	transfers, err := t.store.Load()
+	if err != nil {
+		return false, err
+	}
+
+	found := false
+	for _, tracked := range transfers {
+		if !strings.EqualFold(tracked.DomainName, domain.Name) {
+			continue
+		}
+		if domain.AccountID != 0 && tracked.AccountID != fmt.Sprint(domain.AccountID) {
+			continue
+		}
+
+		tracked.NextCheck, tracked.Interval = time.Time{}, t.MinInterval
+		if err := t.store.Save(tracked); err != nil {
+			return found, err
+		}
+		found = true
+	}
+
+	if found {
+		t.wake()
+	}
+	return found, nil
+}
+
+// Poll checks the state of the transfers that are due, and returns the time of the next check.
+// The transfers that reached a final state are removed from the store.
+//
+// Poll checks all the due transfers even when one of the checks fails,
+// and returns the first error.
+func (t *TransferTracker) Poll(ctx context.Context) (time.Time, error) {
+	transfers, err := t.store.Load()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var next time.Time
+	var firstErr error
+	for _, tracked := range transfers {
+		if err := ctx.Err(); err != nil {
+			return next, err
+		}
+
+		if time.Now().Before(tracked.NextCheck) {
+			if next.IsZero() || tracked.NextCheck.Before(next) {
+				next = tracked.NextCheck
+			}
+			continue
+		}
+
+		tracking, err := t.check(&tracked)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("transfer of %v: %v", tracked.DomainName, err)
+		}
+		if tracking && (next.IsZero() || tracked.NextCheck.Before(next)) {
+			next = tracked.NextCheck
+		}
+	}
+
+	return next, firstErr
+}
+
+// check fetches the state of a transfer, notifies the change and updates the store.
+// It returns true if the transfer is still tracked after the check.
+func (t *TransferTracker) check(tracked *TrackedTransfer) (bool, error) {
+	transferResponse, requestErr := t.client.Registrar.GetDomainTransfer(tracked.AccountID, tracked.DomainName, tracked.Transfer.ID)
+
+	// The transfer is read again, as it may have been checked, rescheduled
+	// or removed while the request was in flight.
+	t.mu.Lock()
+	current, found, err := t.find(tracked.key())
+	if err != nil || !found {
+		t.mu.Unlock()
+		return false, err
+	}
+	*tracked = current
+	previous := tracked.Transfer.State
+
+	// Back off even if the request fails, so that a persistent error doesn't hammer the API.
+	t.schedule(tracked, tracked.Interval)
+	if requestErr == nil {
+		tracked.Transfer = *transferResponse.Data
+		if tracked.Transfer.State != previous {
+			t.schedule(tracked, t.MinInterval)
+		}
+	}
+
+	done := transferDone(tracked.Transfer.State)
+	if done {
+		err = t.store.Delete(*tracked)
+	} else {
+		err = t.store.Save(*tracked)
+	}
+	t.mu.Unlock()
+
+	if requestErr != nil {
+		if err != nil {
+			return true, err
+		}
+		return true, requestErr
+	}
+	if tracked.Transfer.State != previous && t.OnStateChange != nil {
+		t.OnStateChange(TransferStateChange{
+			AccountID:     tracked.AccountID,
+			DomainName:    tracked.DomainName,
+			PreviousState: previous,
+			Transfer:      tracked.Transfer,
+		})
+	}
+	return !done, err
+}
+
+// find returns the stored transfer with the key, if any. It must be called with mu held.
+func (t *TransferTracker) find(key string) (TrackedTransfer, bool, error) {
+	transfers, err := t.store.Load()
+	if err != nil {
+		return TrackedTransfer{}, false, err
+	}
+	for _, tracked := range transfers {
+		if tracked.key() == key {
+			return tracked, true, nil
+		}
+	}
+	return TrackedTransfer{}, false, nil
+}
+
+// schedule sets the next check of a transfer after the interval,
+// and doubles the interval for the check after that.
+func (t *TransferTracker) schedule(tracked *TrackedTransfer, interval time.Duration) {
+	if interval < t.MinInterval {
+		interval = t.MinInterval
+	}
+	if interval > t.MaxInterval {
+		interval = t.MaxInterval
+	}
+
+	tracked.NextCheck = time.Now().Add(interval)
+	tracked.Interval = interval * 2
+}
+
+// Run polls the tracked transfers until the context is done.
+// Poll errors are reported to onError, if not nil, and don't stop the tracker.
+func (t *TransferTracker) Run(ctx context.Context, onError func(error)) error {
+	for {
+		next, err := t.Poll(ctx)
+		if err != nil && ctx.Err() == nil && onError != nil {
+			onError(err)
+		}
+
+		wait := t.MaxInterval
+		if !next.IsZero() {
+			wait = next.Sub(time.Now())
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-t.wakeup:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *TransferTracker) wake() {
+	select {
+	case t.wakeup <- struct{}{}:
+	default:
+	}
+}
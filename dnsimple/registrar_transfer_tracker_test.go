@@ -0,0 +1,215 @@
+package dnsimple
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTransferTracker_Poll(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	serveFixture(t, "/v2/1010/registrar/domains/example.com/transfers/1", "/api/getDomainTransfer/success.http")
+
+	store := NewMemoryTransferStore()
+	tracker := NewTransferTracker(client, store)
+
+	var changes []TransferStateChange
+	tracker.OnStateChange = func(change TransferStateChange) {
+		changes = append(changes, change)
+	}
+
+	if err := tracker.Track("1010", "example.com", DomainTransfer{ID: 1, State: "transferring"}); err != nil {
+		t.Fatalf("Track() returned error: %v", err)
+	}
+	if _, err := tracker.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll() returned error: %v", err)
+	}
+
+	if want, got := 1, len(changes); want != got {
+		t.Fatalf("Poll() expected to notify %v changes, got %v", want, got)
+	}
+	change := changes[0]
+	if want, got := "transferring", change.PreviousState; want != got {
+		t.Errorf("Poll() PreviousState expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := "The auth code is invalid.", change.Transfer.StatusDescription; want != got {
+		t.Errorf("Poll() StatusDescription expected to be `%v`, got `%v`", want, got)
+	}
+	if !change.Done() {
+		t.Errorf("Poll() expected the failed transfer to be done")
+	}
+
+	transfers, _ := store.Load()
+	if want, got := 0, len(transfers); want != got {
+		t.Errorf("Poll() expected the store to have %v transfers, got %v", want, got)
+	}
+}
+
+// raceTransferStore is a MemoryTransferStore that lets a test interleave
+// a webhook event with the completion of a transfer.
+type raceTransferStore struct {
+	*MemoryTransferStore
+
+	hooked  int32
+	loaded  chan struct{}
+	deleted chan struct{}
+}
+
+func (s *raceTransferStore) Load() ([]TrackedTransfer, error) {
+	transfers, err := s.MemoryTransferStore.Load()
+	if atomic.CompareAndSwapInt32(&s.hooked, 1, 2) {
+		close(s.loaded)
+	}
+	return transfers, err
+}
+
+func (s *raceTransferStore) Save(transfer TrackedTransfer) error {
+	if atomic.LoadInt32(&s.hooked) != 0 {
+		// Give the check the chance to complete the transfer in the meantime.
+		select {
+		case <-s.deleted:
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return s.MemoryTransferStore.Save(transfer)
+}
+
+func (s *raceTransferStore) Delete(transfer TrackedTransfer) error {
+	err := s.MemoryTransferStore.Delete(transfer)
+	close(s.deleted)
+	return err
+}
+
+func TestTransferTracker_WebhookDuringPoll(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	store := &raceTransferStore{MemoryTransferStore: NewMemoryTransferStore(), loaded: make(chan struct{}), deleted: make(chan struct{})}
+	tracker := NewTransferTracker(client, store)
+
+	inFlight := make(chan struct{})
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/transfers/1", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/getDomainTransfer/success.http")
+
+		// The webhook event is received while the check is in flight.
+		close(inFlight)
+		select {
+		case <-store.loaded:
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	var changes int32
+	tracker.OnStateChange = func(change TransferStateChange) {
+		atomic.AddInt32(&changes, 1)
+	}
+
+	if err := tracker.Track("1010", "example.com", DomainTransfer{ID: 1, State: "transferring"}); err != nil {
+		t.Fatalf("Track() returned error: %v", err)
+	}
+
+	polled := make(chan error)
+	go func() {
+		_, err := tracker.Poll(context.Background())
+		polled <- err
+	}()
+
+	<-inFlight
+	atomic.StoreInt32(&store.hooked, 1)
+	if _, err := tracker.HandleWebhookEvent("domain.transfer:started", &Domain{Name: "example.com"}); err != nil {
+		t.Fatalf("HandleWebhookEvent() returned error: %v", err)
+	}
+	if err := <-polled; err != nil {
+		t.Fatalf("Poll() returned error: %v", err)
+	}
+
+	if transfers, _ := store.MemoryTransferStore.Load(); len(transfers) != 0 {
+		t.Errorf("HandleWebhookEvent() expected not to restore the completed transfer, got %+v", transfers)
+	}
+	if _, err := tracker.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll() returned error: %v", err)
+	}
+	if want, got := int32(1), atomic.LoadInt32(&changes); want != got {
+		t.Errorf("Poll() expected to notify %v changes, got %v", want, got)
+	}
+}
+
+func TestTransferTracker_Backoff(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	requests := 0
+	mux.HandleFunc("/v2/1010/registrar/domains/example.com/transfers/1", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/transferDomain/success.http")
+
+		requests++
+		testMethod(t, r, "GET")
+
+		w.WriteHeader(http.StatusOK)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	dir, err := ioutil.TempDir("", "dnsimple-transfers")
+	if err != nil {
+		t.Fatalf("TempDir() returned error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tracker := NewTransferTracker(client, &FileTransferStore{Path: filepath.Join(dir, "transfers.json")})
+	tracker.MinInterval = time.Hour
+	tracker.OnStateChange = func(change TransferStateChange) {
+		t.Errorf("OnStateChange() not expected to be called, got %+v", change)
+	}
+
+	if err := tracker.Track("1010", "example.com", DomainTransfer{ID: 1, State: "transferring"}); err != nil {
+		t.Fatalf("Track() returned error: %v", err)
+	}
+
+	next, err := tracker.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("Poll() returned error: %v", err)
+	}
+	if wait := next.Sub(time.Now()); wait < 59*time.Minute {
+		t.Errorf("Poll() expected the next check in an hour, got %v", wait)
+	}
+
+	tracker.Poll(context.Background())
+	if want, got := 1, requests; want != got {
+		t.Errorf("Poll() expected %v requests before the next check, got %v", want, got)
+	}
+
+	transfers, err := tracker.store.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if want, got := 2*time.Hour, transfers[0].Interval; want != got {
+		t.Errorf("Poll() expected the Interval to double to `%v`, got `%v`", want, got)
+	}
+
+	found, err := tracker.HandleWebhookEvent("domain.transfer:started", &Domain{Name: "example.com", AccountID: 1010})
+	if err != nil {
+		t.Fatalf("HandleWebhookEvent() returned error: %v", err)
+	}
+	if !found {
+		t.Errorf("HandleWebhookEvent() expected to correlate the event with the transfer")
+	}
+	if found, _ := tracker.HandleWebhookEvent("domain.create", &Domain{Name: "example.com"}); found {
+		t.Errorf("HandleWebhookEvent() expected to ignore non-transfer events")
+	}
+
+	tracker.Poll(context.Background())
+	if want, got := 2, requests; want != got {
+		t.Errorf("Poll() expected %v requests after the webhook event, got %v", want, got)
+	}
+}
@@ -0,0 +1,317 @@
+package dnsimple
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// SnapshotVersion is the version of the archive format written by AccountSnapshot.WriteJSON.
+const SnapshotVersion = 1
+
+// AccountSnapshot represents everything that can be read from an account
+// with the DNSimple API, and that can be replayed into another account.
+type AccountSnapshot struct {
+	// The version of the archive format.
+	Version int `json:"version"`
+
+	// The ID of the account the snapshot was taken from.
+	AccountID string `json:"account_id"`
+
+	Contacts  []Contact          `json:"contacts"`
+	Domains   []DomainSnapshot   `json:"domains"`
+	Zones     []ZoneSnapshot     `json:"zones"`
+	Templates []TemplateSnapshot `json:"templates"`
+	Webhooks  []Webhook          `json:"webhooks"`
+}
+
+// DomainSnapshot represents a domain along with its email forwards, DNSSEC and delegation state.
+type DomainSnapshot struct {
+	Domain                  Domain                   `json:"domain"`
+	EmailForwards           []EmailForward           `json:"email_forwards"`
+	Dnssec                  *Dnssec                  `json:"dnssec,omitempty"`
+	DelegationSignerRecords []DelegationSignerRecord `json:"delegation_signer_records"`
+
+	// The delegation is only available for domains registered with DNSimple.
+	Delegation *Delegation `json:"delegation,omitempty"`
+}
+
+// ZoneSnapshot represents a zone along with its records.
+type ZoneSnapshot struct {
+	Zone    Zone         `json:"zone"`
+	Records []ZoneRecord `json:"records"`
+}
+
+// TemplateSnapshot represents a template along with its records.
+type TemplateSnapshot struct {
+	Template Template         `json:"template"`
+	Records  []TemplateRecord `json:"records"`
+}
+
+// TakeSnapshot walks every resource reachable from the account
+// and returns them as an AccountSnapshot.
+//
+// The collections are sorted, so that two snapshots of an account
+// in the same state are identical.
+func TakeSnapshot(c *Client, accountID string) (*AccountSnapshot, error) {
+	snapshot := &AccountSnapshot{Version: SnapshotVersion, AccountID: accountID}
+
+	err := eachPage(func(options ListOptions) (*Pagination, error) {
+		contactsResponse, err := c.Contacts.ListContacts(accountID, &options)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Contacts = append(snapshot.Contacts, contactsResponse.Data...)
+		return contactsResponse.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing contacts: %v", err)
+	}
+
+	domains, err := listAllDomains(c, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("listing domains: %v", err)
+	}
+	for _, domain := range domains {
+		domainSnapshot, err := takeDomainSnapshot(c, accountID, domain)
+		if err != nil {
+			return nil, fmt.Errorf("domain %v: %v", domain.Name, err)
+		}
+		snapshot.Domains = append(snapshot.Domains, *domainSnapshot)
+	}
+
+	var zones []Zone
+	err = eachPage(func(options ListOptions) (*Pagination, error) {
+		zonesResponse, err := c.Zones.ListZones(accountID, &ZoneListOptions{ListOptions: options})
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, zonesResponse.Data...)
+		return zonesResponse.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing zones: %v", err)
+	}
+	for _, zone := range zones {
+		records, err := listAllZoneRecords(c, accountID, zone.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("zone %v: %v", zone.Name, err)
+		}
+		snapshot.Zones = append(snapshot.Zones, ZoneSnapshot{Zone: zone, Records: records})
+	}
+
+	var templates []Template
+	err = eachPage(func(options ListOptions) (*Pagination, error) {
+		templatesResponse, err := c.Templates.ListTemplates(accountID, &options)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, templatesResponse.Data...)
+		return templatesResponse.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing templates: %v", err)
+	}
+	for _, template := range templates {
+		templateSnapshot := TemplateSnapshot{Template: template}
+		err = eachPage(func(options ListOptions) (*Pagination, error) {
+			recordsResponse, err := c.Templates.ListTemplateRecords(accountID, template.SID, &options)
+			if err != nil {
+				return nil, err
+			}
+			templateSnapshot.Records = append(templateSnapshot.Records, recordsResponse.Data...)
+			return recordsResponse.Pagination, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("template %v: %v", template.SID, err)
+		}
+		snapshot.Templates = append(snapshot.Templates, templateSnapshot)
+	}
+
+	webhooksResponse, err := c.Webhooks.ListWebhooks(accountID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing webhooks: %v", err)
+	}
+	snapshot.Webhooks = webhooksResponse.Data
+
+	snapshot.sort()
+	return snapshot, nil
+}
+
+func takeDomainSnapshot(c *Client, accountID string, domain Domain) (*DomainSnapshot, error) {
+	domainSnapshot := &DomainSnapshot{Domain: domain}
+
+	err := eachPage(func(options ListOptions) (*Pagination, error) {
+		forwardsResponse, err := c.Domains.ListEmailForwards(accountID, domain.Name, &options)
+		if err != nil {
+			return nil, err
+		}
+		domainSnapshot.EmailForwards = append(domainSnapshot.EmailForwards, forwardsResponse.Data...)
+		return forwardsResponse.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing email forwards: %v", err)
+	}
+
+	dnssecResponse, err := c.Domains.GetDnssec(accountID, domain.Name)
+	if err != nil {
+		return nil, fmt.Errorf("getting DNSSEC: %v", err)
+	}
+	domainSnapshot.Dnssec = dnssecResponse.Data
+
+	err = eachPage(func(options ListOptions) (*Pagination, error) {
+		dsRecordsResponse, err := c.Domains.ListDelegationSignerRecords(accountID, domain.Name, &options)
+		if err != nil {
+			return nil, err
+		}
+		domainSnapshot.DelegationSignerRecords = append(domainSnapshot.DelegationSignerRecords, dsRecordsResponse.Data...)
+		return dsRecordsResponse.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing delegation signer records: %v", err)
+	}
+
+	if domain.State == "registered" {
+		delegationResponse, err := c.Registrar.GetDomainDelegation(accountID, domain.Name)
+		if err != nil {
+			return nil, fmt.Errorf("getting delegation: %v", err)
+		}
+		domainSnapshot.Delegation = delegationResponse.Data
+	}
+
+	return domainSnapshot, nil
+}
+
+// listAllDomains returns all the domains in the account, across all the pages.
+func listAllDomains(c *Client, accountID string) ([]Domain, error) {
+	var domains []Domain
+	err := eachPage(func(options ListOptions) (*Pagination, error) {
+		domainsResponse, err := c.Domains.ListDomains(accountID, &DomainListOptions{ListOptions: options})
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, domainsResponse.Data...)
+		return domainsResponse.Pagination, nil
+	})
+	return domains, err
+}
+
+// listAllZoneRecords returns all the records in the zone matching the filters, across all the pages.
+func listAllZoneRecords(c *Client, accountID string, zoneName string, filters *ZoneRecordListOptions) ([]ZoneRecord, error) {
+	var records []ZoneRecord
+	err := eachPage(func(options ListOptions) (*Pagination, error) {
+		recordOptions := &ZoneRecordListOptions{ListOptions: options}
+		if filters != nil {
+			recordOptions.Name, recordOptions.NameLike, recordOptions.Type = filters.Name, filters.NameLike, filters.Type
+		}
+
+		recordsResponse, err := c.Zones.ListRecords(accountID, zoneName, recordOptions)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, recordsResponse.Data...)
+		return recordsResponse.Pagination, nil
+	})
+	return records, err
+}
+
+func (s *AccountSnapshot) sort() {
+	sort.Sort(contactsByID(s.Contacts))
+	sort.Sort(domainSnapshotsByName(s.Domains))
+	sort.Sort(zoneSnapshotsByName(s.Zones))
+	sort.Sort(templateSnapshotsBySID(s.Templates))
+	sort.Sort(webhooksByURL(s.Webhooks))
+
+	for _, domain := range s.Domains {
+		sort.Sort(emailForwardsByID(domain.EmailForwards))
+		sort.Sort(delegationSignerRecordsByID(domain.DelegationSignerRecords))
+	}
+	for _, zone := range s.Zones {
+		sort.Sort(zoneRecordsByID(zone.Records))
+	}
+	for _, template := range s.Templates {
+		sort.Sort(templateRecordsByID(template.Records))
+	}
+}
+
+type contactsByID []Contact
+
+func (s contactsByID) Len() int           { return len(s) }
+func (s contactsByID) Less(i, j int) bool { return s[i].ID < s[j].ID }
+func (s contactsByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type domainSnapshotsByName []DomainSnapshot
+
+func (s domainSnapshotsByName) Len() int           { return len(s) }
+func (s domainSnapshotsByName) Less(i, j int) bool { return s[i].Domain.Name < s[j].Domain.Name }
+func (s domainSnapshotsByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type zoneSnapshotsByName []ZoneSnapshot
+
+func (s zoneSnapshotsByName) Len() int           { return len(s) }
+func (s zoneSnapshotsByName) Less(i, j int) bool { return s[i].Zone.Name < s[j].Zone.Name }
+func (s zoneSnapshotsByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type templateSnapshotsBySID []TemplateSnapshot
+
+func (s templateSnapshotsBySID) Len() int           { return len(s) }
+func (s templateSnapshotsBySID) Less(i, j int) bool { return s[i].Template.SID < s[j].Template.SID }
+func (s templateSnapshotsBySID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type webhooksByURL []Webhook
+
+func (s webhooksByURL) Len() int           { return len(s) }
+func (s webhooksByURL) Less(i, j int) bool { return s[i].URL < s[j].URL }
+func (s webhooksByURL) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type emailForwardsByID []EmailForward
+
+func (s emailForwardsByID) Len() int           { return len(s) }
+func (s emailForwardsByID) Less(i, j int) bool { return s[i].ID < s[j].ID }
+func (s emailForwardsByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type delegationSignerRecordsByID []DelegationSignerRecord
+
+func (s delegationSignerRecordsByID) Len() int           { return len(s) }
+func (s delegationSignerRecordsByID) Less(i, j int) bool { return s[i].ID < s[j].ID }
+func (s delegationSignerRecordsByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type zoneRecordsByID []ZoneRecord
+
+func (s zoneRecordsByID) Len() int           { return len(s) }
+func (s zoneRecordsByID) Less(i, j int) bool { return s[i].ID < s[j].ID }
+func (s zoneRecordsByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type templateRecordsByID []TemplateRecord
+
+func (s templateRecordsByID) Len() int           { return len(s) }
+func (s templateRecordsByID) Less(i, j int) bool { return s[i].ID < s[j].ID }
+func (s templateRecordsByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// WriteJSON writes the snapshot as an indented JSON archive.
+//
+// The output is deterministic. JSON is the only archive format.
+func (s *AccountSnapshot) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// ReadSnapshot reads a snapshot archive written by AccountSnapshot.WriteJSON.
+func ReadSnapshot(r io.Reader) (*AccountSnapshot, error) {
+	snapshot := &AccountSnapshot{}
+	if err := json.NewDecoder(r).Decode(snapshot); err != nil {
+		return nil, err
+	}
+
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %v", snapshot.Version)
+	}
+
+	return snapshot, nil
+}
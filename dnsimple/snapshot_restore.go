@@ -0,0 +1,567 @@
+package dnsimple
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RestoreConflictPolicy defines what a restore does when a resource
+// in the snapshot already exists in the target account with different attributes.
+type RestoreConflictPolicy string
+
+const (
+	// RestoreKeepExisting leaves the existing resource untouched. This is the default.
+	RestoreKeepExisting = RestoreConflictPolicy("keep")
+
+	// RestoreOverwrite replaces the existing resource with the one in the snapshot.
+	RestoreOverwrite = RestoreConflictPolicy("overwrite")
+)
+
+// RestoreOperation identifies the kind of change a restore applies to a resource.
+type RestoreOperation string
+
+const (
+	// RestoreCreate creates a resource missing in the account.
+	RestoreCreate = RestoreOperation("create")
+
+	// RestoreUpdate replaces an existing resource with the one in the snapshot.
+	RestoreUpdate = RestoreOperation("update")
+
+	// RestoreUnchanged is reported for resources that already match the snapshot.
+	RestoreUnchanged = RestoreOperation("unchanged")
+
+	// RestoreConflict is reported for existing resources left untouched because of the conflict policy.
+	RestoreConflict = RestoreOperation("conflict")
+)
+
+// RestoreOptions specifies the optional parameters you can provide
+// to customize RestoreSnapshot.
+type RestoreOptions struct {
+	// Set to true to only report the changes, without applying them.
+	DryRun bool
+
+	// What to do when a resource already exists with different attributes.
+	// Defaults to RestoreKeepExisting.
+	OnConflict RestoreConflictPolicy
+}
+
+// RestoreAction represents a single change of a restore.
+type RestoreAction struct {
+	// The kind of resource, eg. domain, zone_record, contact.
+	Resource string
+
+	// A human-readable identifier of the resource.
+	Name string
+
+	Operation RestoreOperation
+
+	// The error returned by the API when applying the change, if any.
+	Err error
+}
+
+// String returns a human-readable description of the action.
+func (a RestoreAction) String() string {
+	s := fmt.Sprintf("%v %v %v", a.Operation, a.Resource, a.Name)
+	if a.Err != nil {
+		s += fmt.Sprintf(": %v", a.Err)
+	}
+	return s
+}
+
+// RestoreReport lists the changes of a restore, in the order they were applied.
+type RestoreReport struct {
+	DryRun  bool
+	Actions []RestoreAction
+}
+
+// Errors returns the actions that failed.
+func (r *RestoreReport) Errors() []RestoreAction {
+	var failed []RestoreAction
+	for _, action := range r.Actions {
+		if action.Err != nil {
+			failed = append(failed, action)
+		}
+	}
+	return failed
+}
+
+// restorer replays a snapshot into an account.
+type restorer struct {
+	client    *Client
+	accountID string
+	options   RestoreOptions
+	report    *RestoreReport
+}
+
+// record adds an action to the report, applying it with fn unless this is a dry run.
+func (r *restorer) record(resource, name string, operation RestoreOperation, fn func() error) {
+	action := RestoreAction{Resource: resource, Name: name, Operation: operation}
+	if !r.options.DryRun && fn != nil {
+		action.Err = fn()
+	}
+	r.report.Actions = append(r.report.Actions, action)
+}
+
+// conflict records a change to an existing resource, according to the conflict policy.
+func (r *restorer) conflict(resource, name string, operation RestoreOperation, fn func() error) {
+	if r.options.OnConflict != RestoreOverwrite {
+		r.record(resource, name, RestoreConflict, nil)
+		return
+	}
+	r.record(resource, name, operation, fn)
+}
+
+// RestoreSnapshot replays a snapshot into the account, for example the sandbox.
+//
+// Missing contacts, domains, email forwards, zone records, templates and webhooks are created.
+// Resources that exist with different attributes are handled according to options.OnConflict.
+// Domains are restored as hosted domains: registrations are never replayed,
+// and the delegation is only changed for domains already registered in the account.
+//
+// The returned error is only set when the state of the account can't be read.
+// The errors of the single changes are reported in the RestoreReport.
+func RestoreSnapshot(c *Client, accountID string, snapshot *AccountSnapshot, options *RestoreOptions) (*RestoreReport, error) {
+	r := &restorer{client: c, accountID: accountID, report: &RestoreReport{}}
+	if options != nil {
+		r.options = *options
+	}
+	r.report.DryRun = r.options.DryRun
+
+	steps := []func(*AccountSnapshot) error{
+		r.restoreContacts,
+		r.restoreDomains,
+		r.restoreZones,
+		r.restoreTemplates,
+		r.restoreWebhooks,
+	}
+	for _, step := range steps {
+		if err := step(snapshot); err != nil {
+			return r.report, err
+		}
+	}
+
+	return r.report, nil
+}
+
+func (r *restorer) restoreContacts(snapshot *AccountSnapshot) error {
+	var existing []Contact
+	err := eachPage(func(options ListOptions) (*Pagination, error) {
+		contactsResponse, err := r.client.Contacts.ListContacts(r.accountID, &options)
+		if err != nil {
+			return nil, err
+		}
+		existing = append(existing, contactsResponse.Data...)
+		return contactsResponse.Pagination, nil
+	})
+	if err != nil {
+		return fmt.Errorf("listing contacts: %v", err)
+	}
+
+	for _, contact := range snapshot.Contacts {
+		contact := restorableContact(contact)
+		name := fmt.Sprintf("%v %v <%v>", contact.FirstName, contact.LastName, contact.Email)
+
+		var match *Contact
+		for i := range existing {
+			if existing[i].Email == contact.Email && existing[i].FirstName == contact.FirstName && existing[i].LastName == contact.LastName {
+				match = &existing[i]
+				break
+			}
+		}
+
+		switch {
+		case match == nil:
+			r.record("contact", name, RestoreCreate, func() error {
+				_, err := r.client.Contacts.CreateContact(r.accountID, contact)
+				return err
+			})
+		case reflect.DeepEqual(restorableContact(*match), contact):
+			r.record("contact", name, RestoreUnchanged, nil)
+		default:
+			contactID := match.ID
+			r.conflict("contact", name, RestoreUpdate, func() error {
+				_, err := r.client.Contacts.UpdateContact(r.accountID, contactID, contact)
+				return err
+			})
+		}
+	}
+
+	return nil
+}
+
+// restorableContact strips the attributes assigned by the API from a contact.
+func restorableContact(contact Contact) Contact {
+	contact.ID, contact.AccountID, contact.CreatedAt, contact.UpdatedAt = 0, 0, "", ""
+	return contact
+}
+
+func (r *restorer) restoreDomains(snapshot *AccountSnapshot) error {
+	existing, err := listAllDomains(r.client, r.accountID)
+	if err != nil {
+		return fmt.Errorf("listing domains: %v", err)
+	}
+
+	domains := map[string]Domain{}
+	for _, domain := range existing {
+		domains[domain.Name] = domain
+	}
+
+	for _, domainSnapshot := range snapshot.Domains {
+		name := domainSnapshot.Domain.Name
+
+		domain, found := domains[name]
+		if !found {
+			failed := false
+			r.record("domain", name, RestoreCreate, func() error {
+				_, err := r.client.Domains.CreateDomain(r.accountID, Domain{Name: name})
+				failed = err != nil
+				return err
+			})
+			if failed {
+				continue
+			}
+		} else {
+			r.record("domain", name, RestoreUnchanged, nil)
+		}
+
+		if err := r.restoreEmailForwards(name, domainSnapshot.EmailForwards, !found); err != nil {
+			return err
+		}
+		if err := r.restoreDnssec(name, domainSnapshot, !found); err != nil {
+			return err
+		}
+		if domain.State == "registered" && domainSnapshot.Delegation != nil {
+			if err := r.restoreDelegation(name, *domainSnapshot.Delegation); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *restorer) restoreEmailForwards(domainName string, forwards []EmailForward, created bool) error {
+	existing := map[string]EmailForward{}
+	if !created {
+		err := eachPage(func(options ListOptions) (*Pagination, error) {
+			forwardsResponse, err := r.client.Domains.ListEmailForwards(r.accountID, domainName, &options)
+			if err != nil {
+				return nil, err
+			}
+			for _, forward := range forwardsResponse.Data {
+				existing[forward.From] = forward
+			}
+			return forwardsResponse.Pagination, nil
+		})
+		if err != nil {
+			return fmt.Errorf("listing email forwards for %v: %v", domainName, err)
+		}
+	}
+
+	for _, forward := range forwards {
+		forward := EmailForward{From: forward.From, To: forward.To}
+		name := fmt.Sprintf("%v -> %v", forward.From, forward.To)
+
+		current, found := existing[forward.From]
+		switch {
+		case !found:
+			r.record("email_forward", name, RestoreCreate, func() error {
+				_, err := r.client.Domains.CreateEmailForward(r.accountID, domainName, forward)
+				return err
+			})
+		case current.To == forward.To:
+			r.record("email_forward", name, RestoreUnchanged, nil)
+		default:
+			// Email forwards can't be updated, they are replaced.
+			r.conflict("email_forward", name, RestoreUpdate, func() error {
+				if _, err := r.client.Domains.DeleteEmailForward(r.accountID, domainName, current.ID); err != nil {
+					return err
+				}
+				_, err := r.client.Domains.CreateEmailForward(r.accountID, domainName, forward)
+				return err
+			})
+		}
+	}
+
+	return nil
+}
+
+func (r *restorer) restoreDnssec(domainName string, domainSnapshot DomainSnapshot, created bool) error {
+	if domainSnapshot.Dnssec == nil || !domainSnapshot.Dnssec.Enabled {
+		return nil
+	}
+
+	enabled := false
+	if !created {
+		dnssecResponse, err := r.client.Domains.GetDnssec(r.accountID, domainName)
+		if err != nil {
+			return fmt.Errorf("getting DNSSEC for %v: %v", domainName, err)
+		}
+		enabled = dnssecResponse.Data != nil && dnssecResponse.Data.Enabled
+	}
+
+	if enabled {
+		r.record("dnssec", domainName, RestoreUnchanged, nil)
+	} else {
+		r.record("dnssec", domainName, RestoreCreate, func() error {
+			_, err := r.client.Domains.EnableDnssec(r.accountID, domainName)
+			return err
+		})
+	}
+
+	existing := map[string]bool{}
+	if !created {
+		err := eachPage(func(options ListOptions) (*Pagination, error) {
+			dsRecordsResponse, err := r.client.Domains.ListDelegationSignerRecords(r.accountID, domainName, &options)
+			if err != nil {
+				return nil, err
+			}
+			for _, dsRecord := range dsRecordsResponse.Data {
+				existing[dsRecord.Digest] = true
+			}
+			return dsRecordsResponse.Pagination, nil
+		})
+		if err != nil {
+			return fmt.Errorf("listing delegation signer records for %v: %v", domainName, err)
+		}
+	}
+
+	for _, dsRecord := range domainSnapshot.DelegationSignerRecords {
+		dsRecord := DelegationSignerRecord{Algorithm: dsRecord.Algorithm, Digest: dsRecord.Digest, DigestType: dsRecord.DigestType, Keytag: dsRecord.Keytag}
+		name := fmt.Sprintf("%v keytag %v", domainName, dsRecord.Keytag)
+
+		if existing[dsRecord.Digest] {
+			r.record("delegation_signer_record", name, RestoreUnchanged, nil)
+			continue
+		}
+		r.record("delegation_signer_record", name, RestoreCreate, func() error {
+			_, err := r.client.Domains.CreateDelegationSignerRecord(r.accountID, domainName, dsRecord)
+			return err
+		})
+	}
+
+	return nil
+}
+
+func (r *restorer) restoreDelegation(domainName string, delegation Delegation) error {
+	delegationResponse, err := r.client.Registrar.GetDomainDelegation(r.accountID, domainName)
+	if err != nil {
+		return fmt.Errorf("getting delegation for %v: %v", domainName, err)
+	}
+
+	if delegationResponse.Data != nil && reflect.DeepEqual(*delegationResponse.Data, delegation) {
+		r.record("delegation", domainName, RestoreUnchanged, nil)
+		return nil
+	}
+
+	r.conflict("delegation", domainName, RestoreUpdate, func() error {
+		_, err := r.client.Registrar.ChangeDomainDelegation(r.accountID, domainName, &delegation)
+		return err
+	})
+	return nil
+}
+
+// singleValueRecordTypes are the record types that can't have more than one record with the same name.
+var singleValueRecordTypes = map[string]bool{
+	"ALIAS": true,
+	"CNAME": true,
+	"URL":   true,
+}
+
+func (r *restorer) restoreZones(snapshot *AccountSnapshot) error {
+	for _, zoneSnapshot := range snapshot.Zones {
+		zoneName := zoneSnapshot.Zone.Name
+
+		// Zones can't be created directly, they are created along with their domain.
+		// In a dry run the zone of a domain that still has to be created doesn't exist yet.
+		existing, err := listAllZoneRecords(r.client, r.accountID, zoneName, nil)
+		if err != nil && !r.options.DryRun {
+			r.report.Actions = append(r.report.Actions, RestoreAction{Resource: "zone", Name: zoneName, Operation: RestoreCreate, Err: err})
+			continue
+		}
+
+		for _, record := range zoneSnapshot.Records {
+			if record.SystemRecord {
+				continue
+			}
+
+			record := ZoneRecord{Name: record.Name, Type: record.Type, Content: record.Content, TTL: record.TTL, Priority: record.Priority, Regions: record.Regions}
+			name := fmt.Sprintf("%v %v %v", fqdn(record.Name, zoneName), record.Type, record.Content)
+
+			var match, sameName *ZoneRecord
+			for i := range existing {
+				current := &existing[i]
+				if current.Name != record.Name || current.Type != record.Type {
+					continue
+				}
+				sameName = current
+				if current.Content == record.Content {
+					match = current
+					break
+				}
+			}
+
+			switch {
+			case match != nil && match.TTL == record.TTL && match.Priority == record.Priority:
+				r.record("zone_record", name, RestoreUnchanged, nil)
+			case match != nil:
+				recordID := match.ID
+				r.conflict("zone_record", name, RestoreUpdate, func() error {
+					_, err := r.client.Zones.UpdateRecord(r.accountID, zoneName, recordID, record)
+					return err
+				})
+			case sameName != nil && singleValueRecordTypes[record.Type]:
+				recordID := sameName.ID
+				r.conflict("zone_record", name, RestoreUpdate, func() error {
+					_, err := r.client.Zones.UpdateRecord(r.accountID, zoneName, recordID, record)
+					return err
+				})
+			default:
+				r.record("zone_record", name, RestoreCreate, func() error {
+					_, err := r.client.Zones.CreateRecord(r.accountID, zoneName, record)
+					return err
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// fqdn returns the fully qualified name of a record name in the zone.
+func fqdn(name string, zoneName string) string {
+	if name == "" {
+		return zoneName
+	}
+	return name + "." + zoneName
+}
+
+func (r *restorer) restoreTemplates(snapshot *AccountSnapshot) error {
+	existing := map[string]Template{}
+	err := eachPage(func(options ListOptions) (*Pagination, error) {
+		templatesResponse, err := r.client.Templates.ListTemplates(r.accountID, &options)
+		if err != nil {
+			return nil, err
+		}
+		for _, template := range templatesResponse.Data {
+			existing[template.SID] = template
+		}
+		return templatesResponse.Pagination, nil
+	})
+	if err != nil {
+		return fmt.Errorf("listing templates: %v", err)
+	}
+
+	for _, templateSnapshot := range snapshot.Templates {
+		template := Template{SID: templateSnapshot.Template.SID, Name: templateSnapshot.Template.Name, Description: templateSnapshot.Template.Description}
+
+		current, found := existing[template.SID]
+		switch {
+		case !found:
+			failed := false
+			r.record("template", template.SID, RestoreCreate, func() error {
+				_, err := r.client.Templates.CreateTemplate(r.accountID, template)
+				failed = err != nil
+				return err
+			})
+			if failed {
+				continue
+			}
+		case current.Name == template.Name && current.Description == template.Description:
+			r.record("template", template.SID, RestoreUnchanged, nil)
+		default:
+			r.conflict("template", template.SID, RestoreUpdate, func() error {
+				_, err := r.client.Templates.UpdateTemplate(r.accountID, template.SID, template)
+				return err
+			})
+		}
+
+		if err := r.restoreTemplateRecords(template.SID, templateSnapshot.Records, !found); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *restorer) restoreTemplateRecords(templateSID string, records []TemplateRecord, created bool) error {
+	var existing []TemplateRecord
+	if !created {
+		err := eachPage(func(options ListOptions) (*Pagination, error) {
+			recordsResponse, err := r.client.Templates.ListTemplateRecords(r.accountID, templateSID, &options)
+			if err != nil {
+				return nil, err
+			}
+			existing = append(existing, recordsResponse.Data...)
+			return recordsResponse.Pagination, nil
+		})
+		if err != nil {
+			return fmt.Errorf("listing records for template %v: %v", templateSID, err)
+		}
+	}
+
+	for _, record := range records {
+		record := TemplateRecord{Name: record.Name, Type: record.Type, Content: record.Content, TTL: record.TTL, Priority: record.Priority}
+		recordName := record.Name
+		if recordName == "" {
+			recordName = "@"
+		}
+		name := fmt.Sprintf("%v %v %v %v", templateSID, recordName, record.Type, record.Content)
+
+		var match *TemplateRecord
+		for i := range existing {
+			if existing[i].Name == record.Name && existing[i].Type == record.Type && existing[i].Content == record.Content {
+				match = &existing[i]
+				break
+			}
+		}
+
+		switch {
+		case match == nil:
+			r.record("template_record", name, RestoreCreate, func() error {
+				_, err := r.client.Templates.CreateTemplateRecord(r.accountID, templateSID, record)
+				return err
+			})
+		case match.TTL == record.TTL && match.Priority == record.Priority:
+			r.record("template_record", name, RestoreUnchanged, nil)
+		default:
+			// Template records can't be updated, they are replaced.
+			recordID := match.ID
+			r.conflict("template_record", name, RestoreUpdate, func() error {
+				if _, err := r.client.Templates.DeleteTemplateRecord(r.accountID, templateSID, recordID); err != nil {
+					return err
+				}
+				_, err := r.client.Templates.CreateTemplateRecord(r.accountID, templateSID, record)
+				return err
+			})
+		}
+	}
+
+	return nil
+}
+
+func (r *restorer) restoreWebhooks(snapshot *AccountSnapshot) error {
+	webhooksResponse, err := r.client.Webhooks.ListWebhooks(r.accountID, nil)
+	if err != nil {
+		return fmt.Errorf("listing webhooks: %v", err)
+	}
+
+	existing := map[string]bool{}
+	for _, webhook := range webhooksResponse.Data {
+		existing[webhook.URL] = true
+	}
+
+	for _, webhook := range snapshot.Webhooks {
+		url := webhook.URL
+		if existing[url] {
+			r.record("webhook", url, RestoreUnchanged, nil)
+			continue
+		}
+		r.record("webhook", url, RestoreCreate, func() error {
+			_, err := r.client.Webhooks.CreateWebhook(r.accountID, Webhook{URL: url})
+			return err
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package dnsimple
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func restoreTestSnapshot() *AccountSnapshot {
+	return &AccountSnapshot{
+		Version: SnapshotVersion,
+		Contacts: []Contact{
+			{ID: 1, FirstName: "First", LastName: "User", Email: "first@example.com", City: "Milano"},
+			{ID: 3, FirstName: "Third", LastName: "User", Email: "third@example.com"},
+		},
+		Domains: []DomainSnapshot{
+			{
+				Domain: Domain{Name: "example-alpha.com"},
+				EmailForwards: []EmailForward{
+					{From: "john@a-domain.com", To: "jane@example.com"},
+					{From: "new@a-domain.com", To: "new@example.com"},
+				},
+			},
+			{Domain: Domain{Name: "example-new.com"}},
+		},
+		Zones: []ZoneSnapshot{
+			{
+				Zone: Zone{Name: "example-alpha.com"},
+				Records: []ZoneRecord{
+					{Name: "", Type: "NS", Content: "ns1.dnsimple.com", SystemRecord: true},
+					{Name: "www", Type: "A", Content: "127.0.0.1", TTL: 3600},
+				},
+			},
+			{
+				Zone:    Zone{Name: "example-new.com"},
+				Records: []ZoneRecord{{Name: "", Type: "A", Content: "127.0.0.2"}},
+			},
+		},
+		Templates: []TemplateSnapshot{
+			{
+				Template: Template{SID: "gamma", Name: "Gamma"},
+				Records:  []TemplateRecord{{Name: "", Type: "A", Content: "127.0.0.3", TTL: 3600}},
+			},
+		},
+		Webhooks: []Webhook{{URL: "https://webhook.test"}},
+	}
+}
+
+func setupRestoreFixtures(t *testing.T) {
+	serveFixture(t, "/v2/2020/contacts", "/api/listContacts/success.http")
+	serveFixture(t, "/v2/2020/zones/example-alpha.com/records", "/api/listZoneRecords/success.http")
+	serveFixture(t, "/v2/2020/webhooks", "/api/listWebhooks/success.http")
+
+	mux.HandleFunc("/v2/2020/domains", func(w http.ResponseWriter, r *http.Request) {
+		fixture := "/api/listDomains/success.http"
+		if r.Method == "POST" {
+			fixture = "/api/createDomain/created.http"
+		}
+		httpResponse := httpResponseFixture(t, fixture)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	mux.HandleFunc("/v2/2020/templates", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+
+		io.WriteString(w, `{"data":[],"pagination":{"current_page":1,"per_page":100,"total_entries":0,"total_pages":1}}`)
+	})
+}
+
+func restoreOperations(report *RestoreReport) []string {
+	var operations []string
+	for _, action := range report.Actions {
+		operations = append(operations, string(action.Operation)+" "+action.Resource+" "+action.Name)
+	}
+	return operations
+}
+
+func TestRestoreSnapshot_DryRun(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	setupRestoreFixtures(t)
+	mux.HandleFunc("/v2/2020/domains/example-alpha.com/email_forwards", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/listEmailForwards/success.http")
+
+		testMethod(t, r, "GET")
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	report, err := RestoreSnapshot(client, "2020", restoreTestSnapshot(), &RestoreOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("RestoreSnapshot() returned error: %v", err)
+	}
+
+	want := []string{
+		"conflict contact First User <first@example.com>",
+		"create contact Third User <third@example.com>",
+		"unchanged domain example-alpha.com",
+		"conflict email_forward john@a-domain.com -> jane@example.com",
+		"create email_forward new@a-domain.com -> new@example.com",
+		"create domain example-new.com",
+		"create zone_record www.example-alpha.com A 127.0.0.1",
+		"create zone_record example-new.com A 127.0.0.2",
+		"create template gamma",
+		"create template_record gamma @ A 127.0.0.3",
+		"unchanged webhook https://webhook.test",
+	}
+	if got := restoreOperations(report); !reflect.DeepEqual(want, got) {
+		t.Errorf("RestoreSnapshot() actions = %#v, want %#v", got, want)
+	}
+	if !report.DryRun {
+		t.Errorf("RestoreSnapshot() expected the report to be a dry run")
+	}
+}
+
+func TestRestoreSnapshot_Overwrite(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	setupRestoreFixtures(t)
+
+	var requests []string
+	track := func(fixture string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			filename := fixture
+			if r.Method == "GET" {
+				filename = "/api/listEmailForwards/success.http"
+			} else {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+			}
+			httpResponse := httpResponseFixture(t, filename)
+
+			w.WriteHeader(httpResponse.StatusCode)
+			io.Copy(w, httpResponse.Body)
+		}
+	}
+	mux.HandleFunc("/v2/2020/domains/example-alpha.com/email_forwards", track("/api/createEmailForward/created.http"))
+	mux.HandleFunc("/v2/2020/domains/example-alpha.com/email_forwards/17703", track("/api/deleteEmailForward/success.http"))
+	mux.HandleFunc("/v2/2020/contacts/1", track("/api/updateContact/success.http"))
+
+	snapshot := restoreTestSnapshot()
+	snapshot.Domains = snapshot.Domains[:1]
+	snapshot.Zones, snapshot.Templates, snapshot.Contacts = nil, nil, snapshot.Contacts[:1]
+
+	report, err := RestoreSnapshot(client, "2020", snapshot, &RestoreOptions{OnConflict: RestoreOverwrite})
+	if err != nil {
+		t.Fatalf("RestoreSnapshot() returned error: %v", err)
+	}
+	if failed := report.Errors(); len(failed) > 0 {
+		t.Fatalf("RestoreSnapshot() returned action errors: %v", failed)
+	}
+
+	want := []string{
+		"PATCH /v2/2020/contacts/1",
+		"DELETE /v2/2020/domains/example-alpha.com/email_forwards/17703",
+		"POST /v2/2020/domains/example-alpha.com/email_forwards",
+		"POST /v2/2020/domains/example-alpha.com/email_forwards",
+	}
+	if !reflect.DeepEqual(want, requests) {
+		t.Errorf("RestoreSnapshot() requests = %#v, want %#v", requests, want)
+	}
+}
+
+func TestRestoreSnapshot_TemplateRecords(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	serveFixture(t, "/v2/2020/contacts", "/api/listContacts/success.http")
+	serveFixture(t, "/v2/2020/domains", "/api/listDomains/success.http")
+	serveFixture(t, "/v2/2020/webhooks", "/api/listWebhooks/success.http")
+	serveFixture(t, "/v2/2020/templates", "/api/listTemplates/success.http")
+
+	var requests []string
+	mux.HandleFunc("/v2/2020/templates/alpha/records", func(w http.ResponseWriter, r *http.Request) {
+		fixture := "/api/listTemplateRecords/success.http"
+		if r.Method == "POST" {
+			fixture = "/api/createTemplateRecord/created.http"
+			requests = append(requests, r.Method+" "+r.URL.Path)
+		}
+		httpResponse := httpResponseFixture(t, fixture)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+	mux.HandleFunc("/v2/2020/templates/alpha/records/298", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/deleteTemplateRecord/success.http")
+
+		testMethod(t, r, "DELETE")
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	snapshot := &AccountSnapshot{
+		Version: SnapshotVersion,
+		Templates: []TemplateSnapshot{
+			{
+				Template: Template{SID: "alpha", Name: "Alpha", Description: "An alpha template."},
+				Records: []TemplateRecord{
+					{Name: "", Type: "A", Content: "192.168.1.1", TTL: 3600},
+					{Name: "www", Type: "CNAME", Content: "{{domain}}", TTL: 600},
+					{Name: "", Type: "MX", Content: "mx.example.com", TTL: 3600, Priority: 10},
+				},
+			},
+		},
+	}
+
+	report, err := RestoreSnapshot(client, "2020", snapshot, nil)
+	if err != nil {
+		t.Fatalf("RestoreSnapshot() returned error: %v", err)
+	}
+
+	want := []string{
+		"unchanged template alpha",
+		"unchanged template_record alpha @ A 192.168.1.1",
+		"conflict template_record alpha www CNAME {{domain}}",
+		"create template_record alpha @ MX mx.example.com",
+	}
+	if got := restoreOperations(report); !reflect.DeepEqual(want, got) {
+		t.Errorf("RestoreSnapshot() actions = %#v, want %#v", got, want)
+	}
+
+	report, err = RestoreSnapshot(client, "2020", snapshot, &RestoreOptions{OnConflict: RestoreOverwrite})
+	if err != nil {
+		t.Fatalf("RestoreSnapshot() returned error: %v", err)
+	}
+	if failed := report.Errors(); len(failed) > 0 {
+		t.Fatalf("RestoreSnapshot() returned action errors: %v", failed)
+	}
+
+	wantRequests := []string{
+		"POST /v2/2020/templates/alpha/records",
+		"DELETE /v2/2020/templates/alpha/records/298",
+		"POST /v2/2020/templates/alpha/records",
+		"POST /v2/2020/templates/alpha/records",
+	}
+	if !reflect.DeepEqual(wantRequests, requests) {
+		t.Errorf("RestoreSnapshot() requests = %#v, want %#v", requests, wantRequests)
+	}
+}
@@ -0,0 +1,114 @@
+package dnsimple
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func setupSnapshotFixtures(t *testing.T) {
+	serveFixture(t, "/v2/1010/contacts", "/api/listContacts/success.http")
+	serveFixture(t, "/v2/1010/domains", "/api/listDomains/success.http")
+	serveFixture(t, "/v2/1010/zones", "/api/listZones/success.http")
+	serveFixture(t, "/v2/1010/templates", "/api/listTemplates/success.http")
+	serveFixture(t, "/v2/1010/webhooks", "/api/listWebhooks/success.http")
+	serveFixture(t, "/v2/1010/registrar/domains/example-beta.com/delegation", "/api/getDomainDelegation/success.http")
+
+	for _, name := range []string{"example-alpha.com", "example-beta.com"} {
+		serveFixture(t, "/v2/1010/domains/"+name+"/email_forwards", "/api/listEmailForwards/success.http")
+		serveFixture(t, "/v2/1010/domains/"+name+"/dnssec", "/api/getDnssec/success.http")
+		serveFixture(t, "/v2/1010/domains/"+name+"/ds_records", "/api/listDelegationSignerRecords/success.http")
+		serveFixture(t, "/v2/1010/zones/"+name+"/records", "/api/listZoneRecords/success.http")
+	}
+	for _, sid := range []string{"alpha", "beta"} {
+		serveFixture(t, "/v2/1010/templates/"+sid+"/records", "/api/listTemplateRecords/success.http")
+	}
+}
+
+func TestTakeSnapshot(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	setupSnapshotFixtures(t)
+
+	snapshot, err := TakeSnapshot(client, "1010")
+	if err != nil {
+		t.Fatalf("TakeSnapshot() returned error: %v", err)
+	}
+
+	if want, got := SnapshotVersion, snapshot.Version; want != got {
+		t.Errorf("TakeSnapshot() Version expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := 2, len(snapshot.Contacts); want != got {
+		t.Errorf("TakeSnapshot() expected to return %v contacts, got %v", want, got)
+	}
+	if want, got := 2, len(snapshot.Templates[0].Records); want != got {
+		t.Errorf("TakeSnapshot() expected to return %v template records, got %v", want, got)
+	}
+	if want, got := 5, len(snapshot.Zones[1].Records); want != got {
+		t.Errorf("TakeSnapshot() expected to return %v zone records, got %v", want, got)
+	}
+	if want, got := "https://another.test", snapshot.Webhooks[0].URL; want != got {
+		t.Errorf("TakeSnapshot() expected webhooks to be sorted, got %v", got)
+	}
+
+	alpha, beta := snapshot.Domains[0], snapshot.Domains[1]
+	if alpha.Delegation != nil {
+		t.Errorf("TakeSnapshot() expected no delegation for a hosted domain, got %v", alpha.Delegation)
+	}
+	if want, got := 4, len(*beta.Delegation); want != got {
+		t.Errorf("TakeSnapshot() expected %v name servers, got %v", want, got)
+	}
+	if !beta.Dnssec.Enabled {
+		t.Errorf("TakeSnapshot() expected DNSSEC to be enabled")
+	}
+	if want, got := "44620", beta.DelegationSignerRecords[0].Keytag; want != got {
+		t.Errorf("TakeSnapshot() DS record Keytag expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := 2, len(beta.EmailForwards); want != got {
+		t.Errorf("TakeSnapshot() expected to return %v email forwards, got %v", want, got)
+	}
+}
+
+func TestAccountSnapshot_WriteJSON(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	setupSnapshotFixtures(t)
+
+	var archives []string
+	for i := 0; i < 2; i++ {
+		snapshot, err := TakeSnapshot(client, "1010")
+		if err != nil {
+			t.Fatalf("TakeSnapshot() returned error: %v", err)
+		}
+
+		buf := &bytes.Buffer{}
+		if err := snapshot.WriteJSON(buf); err != nil {
+			t.Fatalf("WriteJSON() returned error: %v", err)
+		}
+		archives = append(archives, buf.String())
+	}
+
+	if archives[0] != archives[1] {
+		t.Errorf("WriteJSON() expected to be deterministic")
+	}
+
+	snapshot, err := ReadSnapshot(strings.NewReader(archives[0]))
+	if err != nil {
+		t.Fatalf("ReadSnapshot() returned error: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	snapshot.WriteJSON(buf)
+	if !reflect.DeepEqual(archives[0], buf.String()) {
+		t.Errorf("ReadSnapshot() expected to read back the same snapshot")
+	}
+}
+
+func TestReadSnapshot_UnsupportedVersion(t *testing.T) {
+	if _, err := ReadSnapshot(strings.NewReader(`{"version": 99}`)); err == nil {
+		t.Errorf("ReadSnapshot() expected to return an error for an unsupported version")
+	}
+}
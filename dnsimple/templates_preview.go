@@ -0,0 +1,242 @@
+package dnsimple
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// templateDomainVariable is the placeholder replaced by the name of the domain the template is applied to.
+const templateDomainVariable = "domain"
+
+var templatePlaceholderRegexp = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// RenderTemplateRecords renders the records of a template for a domain.
+//
+// The placeholders {{name}} in the name and the content of the records are
+// replaced by the value of the variable. {{domain}} is replaced by the name
+// of the domain, unless the variables define it. It returns an error listing
+// the undefined variables.
+func RenderTemplateRecords(records []TemplateRecord, domainName string, variables map[string]string) ([]ZoneRecord, error) {
+	undefined := map[string]bool{}
+	render := func(text string) string {
+		return templatePlaceholderRegexp.ReplaceAllStringFunc(text, func(placeholder string) string {
+			name := templatePlaceholderRegexp.FindStringSubmatch(placeholder)[1]
+			if value, ok := variables[name]; ok {
+				return value
+			}
+			if name == templateDomainVariable {
+				return domainName
+			}
+			undefined[name] = true
+			return placeholder
+		})
+	}
+
+	rendered := make([]ZoneRecord, len(records))
+	for i, record := range records {
+		rendered[i] = ZoneRecord{
+			Type:     record.Type,
+			Name:     render(record.Name),
+			Content:  render(record.Content),
+			TTL:      record.TTL,
+			Priority: record.Priority,
+		}
+	}
+
+	if len(undefined) > 0 {
+		names := make([]string, 0, len(undefined))
+		for name := range undefined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("undefined template variables: %v", strings.Join(names, ", "))
+	}
+	return rendered, nil
+}
+
+// TemplateRecordChangeType identifies the outcome of applying a template record to a zone.
+type TemplateRecordChangeType string
+
+const (
+	// The record is created.
+	TemplateRecordCreate = TemplateRecordChangeType("create")
+
+	// The zone already has the record.
+	TemplateRecordExists = TemplateRecordChangeType("exists")
+
+	// The record conflicts with records of the zone, and can't be created.
+	TemplateRecordConflict = TemplateRecordChangeType("conflict")
+)
+
+// TemplateRecordChange represents a rendered template record, compared to the records of the zone.
+type TemplateRecordChange struct {
+	Type   TemplateRecordChangeType
+	Record ZoneRecord
+
+	// The records of the zone, or the other records of the template,
+	// the record conflicts with, if any.
+	Conflicts []ZoneRecord
+}
+
+// TemplatePreview represents the records of a template rendered for a domain,
+// compared to the current records of the zone of the domain.
+type TemplatePreview struct {
+	Domain  string
+	Changes []TemplateRecordChange
+}
+
+// Conflicts returns the changes that conflict with the records of the zone.
+func (p *TemplatePreview) Conflicts() []TemplateRecordChange {
+	var conflicts []TemplateRecordChange
+	for _, change := range p.Changes {
+		if change.Type == TemplateRecordConflict {
+			conflicts = append(conflicts, change)
+		}
+	}
+	return conflicts
+}
+
+// String returns a human-readable diff of the records.
+func (p *TemplatePreview) String() string {
+	lines := []string{p.Domain + ":"}
+	for _, change := range p.Changes {
+		switch change.Type {
+		case TemplateRecordCreate:
+			lines = append(lines, "+ "+formatTemplateRecord(change.Record))
+		case TemplateRecordExists:
+			lines = append(lines, "= "+formatTemplateRecord(change.Record))
+		case TemplateRecordConflict:
+			conflicts := make([]string, len(change.Conflicts))
+			for i, record := range change.Conflicts {
+				conflicts[i] = formatTemplateRecord(record)
+			}
+			lines = append(lines, fmt.Sprintf("! %v conflicts with %v", formatTemplateRecord(change.Record), strings.Join(conflicts, ", ")))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatTemplateRecord(record ZoneRecord) string {
+	name := record.Name
+	if name == "" {
+		name = "@"
+	}
+	if record.Priority != 0 {
+		return fmt.Sprintf("%v %v %v %v", name, record.Type, record.Priority, record.Content)
+	}
+	return fmt.Sprintf("%v %v %v", name, record.Type, record.Content)
+}
+
+// PreviewTemplate renders the records of a template for a domain, see RenderTemplateRecords,
+// and compares them to the current records of the zone of the domain.
+//
+// A record conflicts with the zone when a CNAME record would share its name
+// with other records. The TTL is ignored when looking for existing records.
+func PreviewTemplate(c *Client, accountID string, templateIdentifier string, domainName string, variables map[string]string) (*TemplatePreview, error) {
+	var templateRecords []TemplateRecord
+	err := eachPage(func(options ListOptions) (*Pagination, error) {
+		templateRecordsResponse, err := c.Templates.ListTemplateRecords(accountID, templateIdentifier, &options)
+		if err != nil {
+			return nil, err
+		}
+		templateRecords = append(templateRecords, templateRecordsResponse.Data...)
+		return templateRecordsResponse.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing records for template %v: %v", templateIdentifier, err)
+	}
+
+	records, err := RenderTemplateRecords(templateRecords, domainName, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := listAllZoneRecords(c, accountID, domainName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing records for %v: %v", domainName, err)
+	}
+
+	preview := &TemplatePreview{Domain: domainName}
+	for _, record := range records {
+		preview.Changes = append(preview.Changes, compareTemplateRecord(record, current, records))
+	}
+	return preview, nil
+}
+
+// compareTemplateRecord compares a rendered record to the records of the zone,
+// and to the other records of the template.
+func compareTemplateRecord(record ZoneRecord, current []ZoneRecord, rendered []ZoneRecord) TemplateRecordChange {
+	change := TemplateRecordChange{Type: TemplateRecordCreate, Record: record}
+
+	for _, existing := range current {
+		if templateRecordMatches(existing, record) {
+			change.Type, change.Conflicts = TemplateRecordExists, nil
+			return change
+		}
+		if conflictsWithCNAME(existing, record) {
+			change.Type = TemplateRecordConflict
+			change.Conflicts = append(change.Conflicts, existing)
+		}
+	}
+	for _, other := range rendered {
+		if conflictsWithCNAME(other, record) && !containsZoneRecord(change.Conflicts, other) {
+			change.Type = TemplateRecordConflict
+			change.Conflicts = append(change.Conflicts, other)
+		}
+	}
+	return change
+}
+
+func templateRecordMatches(existing ZoneRecord, record ZoneRecord) bool {
+	return existing.Type == record.Type &&
+		strings.EqualFold(existing.Name, record.Name) &&
+		existing.Content == record.Content &&
+		existing.Priority == record.Priority
+}
+
+// conflictsWithCNAME returns true if the records share a name, and one of them is a CNAME record:
+// a CNAME record can't coexist with other records (RFC 1034, section 3.6.2).
+func conflictsWithCNAME(a ZoneRecord, b ZoneRecord) bool {
+	if !strings.EqualFold(a.Name, b.Name) {
+		return false
+	}
+	return (a.Type == "CNAME" || b.Type == "CNAME") && !templateRecordMatches(a, b)
+}
+
+func containsZoneRecord(records []ZoneRecord, record ZoneRecord) bool {
+	for _, r := range records {
+		if reflect.DeepEqual(r, record) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply creates the records of the preview that are not in the zone yet.
+//
+// It fails without creating any record when the preview has conflicts.
+// The results of the creations are returned in the order of the records.
+func (p *TemplatePreview) Apply(ctx context.Context, c *Client, accountID string, options *BulkOptions) ([]ZoneRecordBulkResult, error) {
+	if conflicts := p.Conflicts(); len(conflicts) > 0 {
+		records := make([]string, len(conflicts))
+		for i, change := range conflicts {
+			records[i] = formatTemplateRecord(change.Record)
+		}
+		return nil, fmt.Errorf("template records conflict with the zone %v: %v", p.Domain, strings.Join(records, "; "))
+	}
+
+	var records []ZoneRecord
+	for _, change := range p.Changes {
+		if change.Type == TemplateRecordCreate {
+			records = append(records, change.Record)
+		}
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return c.Zones.CreateRecords(ctx, accountID, p.Domain, records, options)
+}
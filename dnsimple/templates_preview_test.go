@@ -0,0 +1,175 @@
+package dnsimple
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplateRecords(t *testing.T) {
+	records := []TemplateRecord{
+		{Type: "CNAME", Name: "{{ app }}", Content: "{{app}}.{{region}}.example.net", TTL: 600},
+		{Type: "TXT", Name: "", Content: "site={{domain}}"},
+		{Type: "MX", Name: "", Content: "mx.{{domain}}", Priority: 10},
+	}
+
+	rendered, err := RenderTemplateRecords(records, "example.com", map[string]string{"app": "shop", "region": "eu"})
+	if err != nil {
+		t.Fatalf("RenderTemplateRecords() returned error: %v", err)
+	}
+
+	want := []ZoneRecord{
+		{Type: "CNAME", Name: "shop", Content: "shop.eu.example.net", TTL: 600},
+		{Type: "TXT", Name: "", Content: "site=example.com"},
+		{Type: "MX", Name: "", Content: "mx.example.com", Priority: 10},
+	}
+	if !reflect.DeepEqual(want, rendered) {
+		t.Errorf("RenderTemplateRecords() returned `%+v`, expected `%+v`", rendered, want)
+	}
+
+	// The variables take precedence over the domain.
+	rendered, _ = RenderTemplateRecords(records[1:2], "example.com", map[string]string{"domain": "example.org"})
+	if want, got := "site=example.org", rendered[0].Content; want != got {
+		t.Errorf("RenderTemplateRecords() returned Content `%v`, expected `%v`", got, want)
+	}
+
+	_, err = RenderTemplateRecords(records, "example.com", map[string]string{"app": "shop"})
+	if err == nil || err.Error() != "undefined template variables: region" {
+		t.Errorf("RenderTemplateRecords() expected to return an error for the undefined variables, got `%v`", err)
+	}
+}
+
+func setupTemplatePreviewFixtures(t *testing.T, templateRecords []TemplateRecord, zoneRecords []ZoneRecord) *[]ZoneRecord {
+	var created []ZoneRecord
+
+	mux.HandleFunc("/v2/1010/templates/alpha/records", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		writeData(w, r, http.StatusOK, templateRecords)
+	})
+	mux.HandleFunc("/v2/1010/zones/example.com/records", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			writeData(w, r, http.StatusOK, zoneRecords)
+		case "POST":
+			var record ZoneRecord
+			json.NewDecoder(r.Body).Decode(&record)
+			created = append(created, record)
+			writeData(w, r, http.StatusCreated, record)
+		default:
+			t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+		}
+	})
+
+	return &created
+}
+
+func TestPreviewTemplate(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	created := setupTemplatePreviewFixtures(t,
+		[]TemplateRecord{
+			{Type: "A", Name: "", Content: "{{ip}}"},
+			{Type: "CNAME", Name: "www", Content: "{{domain}}"},
+			{Type: "CNAME", Name: "blog", Content: "blog.example.net"},
+			{Type: "MX", Name: "", Content: "mx.example.net", Priority: 10},
+		},
+		[]ZoneRecord{
+			{ID: 1, Type: "A", Name: "", Content: "192.0.2.1"},
+			{ID: 2, Type: "CNAME", Name: "www", Content: "example.com"},
+			{ID: 3, Type: "A", Name: "blog", Content: "192.0.2.2"},
+			{ID: 4, Type: "TXT", Name: "blog", Content: "verification"},
+		},
+	)
+
+	preview, err := PreviewTemplate(client, "1010", "alpha", "example.com", map[string]string{"ip": "192.0.2.1"})
+	if err != nil {
+		t.Fatalf("PreviewTemplate() returned error: %v", err)
+	}
+
+	var types []TemplateRecordChangeType
+	for _, change := range preview.Changes {
+		types = append(types, change.Type)
+	}
+	want := []TemplateRecordChangeType{TemplateRecordExists, TemplateRecordExists, TemplateRecordConflict, TemplateRecordCreate}
+	if !reflect.DeepEqual(want, types) {
+		t.Errorf("PreviewTemplate() returned changes `%v`, expected `%v`", types, want)
+	}
+
+	wantString := strings.Join([]string{
+		"example.com:",
+		"= @ A 192.0.2.1",
+		"= www CNAME example.com",
+		"! blog CNAME blog.example.net conflicts with blog A 192.0.2.2, blog TXT verification",
+		"+ @ MX 10 mx.example.net",
+	}, "\n")
+	if got := preview.String(); wantString != got {
+		t.Errorf("TemplatePreview.String() expected to be:\n%v\ngot:\n%v", wantString, got)
+	}
+
+	if _, err := preview.Apply(context.Background(), client, "1010", nil); err == nil {
+		t.Errorf("TemplatePreview.Apply() expected to return an error for the conflicts")
+	}
+	if want, got := 0, len(*created); want != got {
+		t.Errorf("TemplatePreview.Apply() created %v records, expected %v", got, want)
+	}
+
+	if _, err := PreviewTemplate(client, "1010", "alpha", "example.com", nil); err == nil {
+		t.Errorf("PreviewTemplate() expected to return an error for the undefined variables")
+	}
+}
+
+func TestPreviewTemplate_ConflictsWithinTemplate(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	setupTemplatePreviewFixtures(t,
+		[]TemplateRecord{
+			{Type: "CNAME", Name: "www", Content: "example.net"},
+			{Type: "A", Name: "www", Content: "192.0.2.1"},
+		},
+		[]ZoneRecord{},
+	)
+
+	preview, err := PreviewTemplate(client, "1010", "alpha", "example.com", nil)
+	if err != nil {
+		t.Fatalf("PreviewTemplate() returned error: %v", err)
+	}
+	if want, got := 2, len(preview.Conflicts()); want != got {
+		t.Errorf("PreviewTemplate() returned %v conflicts, expected %v", got, want)
+	}
+}
+
+func TestTemplatePreview_Apply(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	created := setupTemplatePreviewFixtures(t,
+		[]TemplateRecord{
+			{Type: "A", Name: "", Content: "192.0.2.1"},
+			{Type: "TXT", Name: "{{name}}", Content: "hello", TTL: 300},
+		},
+		[]ZoneRecord{{ID: 1, Type: "A", Name: "", Content: "192.0.2.1"}},
+	)
+
+	preview, err := PreviewTemplate(client, "1010", "alpha", "example.com", map[string]string{"name": "greeting"})
+	if err != nil {
+		t.Fatalf("PreviewTemplate() returned error: %v", err)
+	}
+
+	results, err := preview.Apply(context.Background(), client, "1010", nil)
+	if err != nil {
+		t.Fatalf("TemplatePreview.Apply() returned error: %v", err)
+	}
+	if want, got := 1, len(results); want != got {
+		t.Fatalf("TemplatePreview.Apply() returned %v results, expected %v", got, want)
+	}
+
+	want := []ZoneRecord{{Type: "TXT", Name: "greeting", Content: "hello", TTL: 300}}
+	if !reflect.DeepEqual(want, *created) {
+		t.Errorf("TemplatePreview.Apply() created `%+v`, expected `%+v`", *created, want)
+	}
+}
@@ -0,0 +1,77 @@
+package dnsimple
+
+import (
+	"fmt"
+)
+
+// TemplateRecordReplaceError represents a template record that was replaced,
+// but that couldn't be deleted afterwards. The template holds both records.
+type TemplateRecordReplaceError struct {
+	// The ID of the record that wasn't deleted.
+	TemplateRecordID int64
+
+	// The record created to replace it.
+	Replacement *TemplateRecord
+
+	Err error
+}
+
+// Error implements the error interface.
+func (e *TemplateRecordReplaceError) Error() string {
+	return fmt.Sprintf("template record %v replaced by %v but not deleted: %v", e.TemplateRecordID, e.Replacement.ID, e.Err)
+}
+
+// ReplaceTemplateRecord replaces a template record by a record with the updated
+// attributes, and returns the new record.
+//
+// The API doesn't update template records: the new record is created first,
+// then the previous record is deleted, so the ID of the record changes.
+// The two requests are not atomic: when the previous record can't be deleted,
+// the returned error is a *TemplateRecordReplaceError with the new record.
+//
+// The attributes that are not set keep their current value, the name included.
+// Set the name to @ to move the record to the apex of the domain.
+func ReplaceTemplateRecord(c *Client, accountID string, templateIdentifier string, templateRecordID int64, templateRecordAttributes TemplateRecord) (*TemplateRecord, error) {
+	current, err := c.Templates.GetTemplateRecord(accountID, templateIdentifier, templateRecordID)
+	if err != nil {
+		return nil, err
+	}
+
+	record := TemplateRecord{
+		Name:     current.Data.Name,
+		Content:  current.Data.Content,
+		TTL:      current.Data.TTL,
+		Type:     current.Data.Type,
+		Priority: current.Data.Priority,
+	}
+	switch templateRecordAttributes.Name {
+	case "":
+	case "@":
+		record.Name = ""
+	default:
+		record.Name = templateRecordAttributes.Name
+	}
+	if templateRecordAttributes.Content != "" {
+		record.Content = templateRecordAttributes.Content
+	}
+	if templateRecordAttributes.TTL != 0 {
+		record.TTL = templateRecordAttributes.TTL
+	}
+	if templateRecordAttributes.Type != "" {
+		record.Type = templateRecordAttributes.Type
+	}
+	if templateRecordAttributes.Priority != 0 {
+		record.Priority = templateRecordAttributes.Priority
+	}
+
+	templateRecordResponse, err := c.Templates.CreateTemplateRecord(accountID, templateIdentifier, record)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := c.Templates.DeleteTemplateRecord(accountID, templateIdentifier, templateRecordID); err != nil {
+		return nil, &TemplateRecordReplaceError{TemplateRecordID: templateRecordID, Replacement: templateRecordResponse.Data, Err: err}
+	}
+
+	return templateRecordResponse.Data, nil
+}
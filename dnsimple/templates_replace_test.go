@@ -0,0 +1,101 @@
+package dnsimple
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestReplaceTemplateRecord(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes TemplateRecord
+		want       map[string]interface{}
+	}{
+		{"keeps the current attributes", TemplateRecord{Content: "mx2.example.com", Priority: 20}, map[string]interface{}{"name": "", "content": "mx2.example.com", "ttl": float64(600), "priority": float64(20), "type": "MX"}},
+		{"renames the record", TemplateRecord{Name: "mail"}, map[string]interface{}{"name": "mail", "content": "mx.example.com", "ttl": float64(600), "priority": float64(10), "type": "MX"}},
+		{"moves the record to the apex", TemplateRecord{Name: "@", TTL: 3600}, map[string]interface{}{"name": "", "content": "mx.example.com", "ttl": float64(3600), "priority": float64(10), "type": "MX"}},
+	}
+
+	for _, tt := range tests {
+		setupMockServer()
+
+		var requests []string
+		mux.HandleFunc("/v2/1010/templates/1/records/2", func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			fixture := "/api/getTemplateRecord/success.http"
+			if r.Method == "DELETE" {
+				fixture = "/api/deleteTemplateRecord/success.http"
+			}
+			httpResponse := httpResponseFixture(t, fixture)
+
+			testHeaders(t, r)
+
+			w.WriteHeader(httpResponse.StatusCode)
+			io.Copy(w, httpResponse.Body)
+		})
+		mux.HandleFunc("/v2/1010/templates/1/records", func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			httpResponse := httpResponseFixture(t, "/api/createTemplateRecord/created.http")
+
+			testMethod(t, r, "POST")
+			testHeaders(t, r)
+			testRequestJSON(t, r, tt.want)
+
+			w.WriteHeader(httpResponse.StatusCode)
+			io.Copy(w, httpResponse.Body)
+		})
+
+		record, err := ReplaceTemplateRecord(client, "1010", "1", 2, tt.attributes)
+		if err != nil {
+			t.Fatalf("ReplaceTemplateRecord() %v: returned error: %v", tt.name, err)
+		}
+		if want, got := int64(300), record.ID; want != got {
+			t.Errorf("ReplaceTemplateRecord() %v: returned ID expected to be `%v`, got `%v`", tt.name, want, got)
+		}
+
+		want := []string{"GET /v2/1010/templates/1/records/2", "POST /v2/1010/templates/1/records", "DELETE /v2/1010/templates/1/records/2"}
+		if !reflect.DeepEqual(want, requests) {
+			t.Errorf("ReplaceTemplateRecord() %v: requests expected to be `%v`, got `%v`", tt.name, want, requests)
+		}
+
+		teardownMockServer()
+	}
+}
+
+func TestReplaceTemplateRecord_DeleteFailure(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/templates/1/records/2", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"error"}`))
+			return
+		}
+		httpResponse := httpResponseFixture(t, "/api/getTemplateRecord/success.http")
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+	mux.HandleFunc("/v2/1010/templates/1/records", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/createTemplateRecord/created.http")
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	record, err := ReplaceTemplateRecord(client, "1010", "1", 2, TemplateRecord{Content: "mx2.example.com"})
+	if record != nil {
+		t.Errorf("ReplaceTemplateRecord() expected to return no record, got `%+v`", record)
+	}
+	replaceErr, ok := err.(*TemplateRecordReplaceError)
+	if !ok {
+		t.Fatalf("ReplaceTemplateRecord() expected to return a *TemplateRecordReplaceError, got `%v`", err)
+	}
+	if want, got := int64(2), replaceErr.TemplateRecordID; want != got {
+		t.Errorf("ReplaceTemplateRecord() returned TemplateRecordID expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := int64(300), replaceErr.Replacement.ID; want != got {
+		t.Errorf("ReplaceTemplateRecord() returned Replacement ID expected to be `%v`, got `%v`", want, got)
+	}
+}
@@ -0,0 +1,274 @@
+package dnsimple
+
+import (
+	"time"
+)
+
+// The API returns timestamps as RFC3339 strings, and dates as YYYY-MM-DD strings.
+// The resource fields keep the raw values, so that they serialize unchanged,
+// and the accessors below parse them into a time.Time.
+//
+// An empty value, such as a domain that has no expiration date,
+// is returned as the zero time without an error.
+
+const dateLayout = "2006-01-02"
+
+// parseTimestamp parses a timestamp returned by the API.
+func parseTimestamp(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// parseDate parses a date returned by the API. Timestamps are accepted as well,
+// as some resources return expiration timestamps instead of dates.
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// CreatedAtTime returns the creation time of the Account.
+func (a Account) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(a.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the Account.
+func (a Account) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(a.UpdatedAt)
+}
+
+// CreatedAtTime returns the creation time of the Certificate.
+func (c Certificate) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(c.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the Certificate.
+func (c Certificate) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(c.UpdatedAt)
+}
+
+// ExpiresOnTime returns the expiration date of the Certificate.
+func (c Certificate) ExpiresOnTime() (time.Time, error) {
+	return parseDate(c.ExpiresOn)
+}
+
+// CreatedAtTime returns the creation time of the CertificatePurchase.
+func (c CertificatePurchase) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(c.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the CertificatePurchase.
+func (c CertificatePurchase) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(c.UpdatedAt)
+}
+
+// CreatedAtTime returns the creation time of the CertificateRenewal.
+func (c CertificateRenewal) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(c.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the CertificateRenewal.
+func (c CertificateRenewal) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(c.UpdatedAt)
+}
+
+// CreatedAtTime returns the creation time of the Collaborator.
+func (c Collaborator) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(c.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the Collaborator.
+func (c Collaborator) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(c.UpdatedAt)
+}
+
+// AcceptedAtTime returns the acceptance time of the Collaborator.
+func (c Collaborator) AcceptedAtTime() (time.Time, error) {
+	return parseTimestamp(c.AcceptedAt)
+}
+
+// CreatedAtTime returns the creation time of the Contact.
+func (c Contact) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(c.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the Contact.
+func (c Contact) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(c.UpdatedAt)
+}
+
+// CreatedAtTime returns the creation time of the DelegationSignerRecord.
+func (d DelegationSignerRecord) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(d.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the DelegationSignerRecord.
+func (d DelegationSignerRecord) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(d.UpdatedAt)
+}
+
+// CreatedAtTime returns the creation time of the Domain.
+func (d Domain) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(d.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the Domain.
+func (d Domain) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(d.UpdatedAt)
+}
+
+// ExpiresOnTime returns the expiration date of the Domain.
+func (d Domain) ExpiresOnTime() (time.Time, error) {
+	return parseDate(d.ExpiresOn)
+}
+
+// CreatedAtTime returns the creation time of the DomainPush.
+func (d DomainPush) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(d.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the DomainPush.
+func (d DomainPush) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(d.UpdatedAt)
+}
+
+// AcceptedAtTime returns the acceptance time of the DomainPush.
+func (d DomainPush) AcceptedAtTime() (time.Time, error) {
+	return parseTimestamp(d.AcceptedAt)
+}
+
+// CreatedAtTime returns the creation time of the DomainRegistration.
+func (d DomainRegistration) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(d.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the DomainRegistration.
+func (d DomainRegistration) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(d.UpdatedAt)
+}
+
+// CreatedAtTime returns the creation time of the DomainRenewal.
+func (d DomainRenewal) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(d.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the DomainRenewal.
+func (d DomainRenewal) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(d.UpdatedAt)
+}
+
+// CreatedAtTime returns the creation time of the DomainTransfer.
+func (d DomainTransfer) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(d.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the DomainTransfer.
+func (d DomainTransfer) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(d.UpdatedAt)
+}
+
+// CreatedAtTime returns the creation time of the EmailForward.
+func (e EmailForward) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(e.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the EmailForward.
+func (e EmailForward) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(e.UpdatedAt)
+}
+
+// CreatedAtTime returns the creation time of the Service.
+func (s Service) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(s.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the Service.
+func (s Service) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(s.UpdatedAt)
+}
+
+// CreatedAtTime returns the creation time of the Template.
+func (t Template) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(t.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the Template.
+func (t Template) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(t.UpdatedAt)
+}
+
+// CreatedAtTime returns the creation time of the TemplateRecord.
+func (t TemplateRecord) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(t.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the TemplateRecord.
+func (t TemplateRecord) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(t.UpdatedAt)
+}
+
+// CreatedAtTime returns the creation time of the VanityNameServer.
+func (v VanityNameServer) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(v.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the VanityNameServer.
+func (v VanityNameServer) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(v.UpdatedAt)
+}
+
+// CreatedAtTime returns the creation time of the WhoisPrivacy.
+func (w WhoisPrivacy) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(w.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the WhoisPrivacy.
+func (w WhoisPrivacy) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(w.UpdatedAt)
+}
+
+// ExpiresOnTime returns the expiration date of the WhoisPrivacy.
+func (w WhoisPrivacy) ExpiresOnTime() (time.Time, error) {
+	return parseDate(w.ExpiresOn)
+}
+
+// CreatedAtTime returns the creation time of the WhoisPrivacyRenewal.
+func (w WhoisPrivacyRenewal) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(w.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the WhoisPrivacyRenewal.
+func (w WhoisPrivacyRenewal) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(w.UpdatedAt)
+}
+
+// ExpiresOnTime returns the expiration date of the WhoisPrivacyRenewal.
+func (w WhoisPrivacyRenewal) ExpiresOnTime() (time.Time, error) {
+	return parseDate(w.ExpiresOn)
+}
+
+// CreatedAtTime returns the creation time of the Zone.
+func (z Zone) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(z.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the Zone.
+func (z Zone) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(z.UpdatedAt)
+}
+
+// CreatedAtTime returns the creation time of the ZoneRecord.
+func (z ZoneRecord) CreatedAtTime() (time.Time, error) {
+	return parseTimestamp(z.CreatedAt)
+}
+
+// UpdatedAtTime returns the last update time of the ZoneRecord.
+func (z ZoneRecord) UpdatedAtTime() (time.Time, error) {
+	return parseTimestamp(z.UpdatedAt)
+}
@@ -0,0 +1,107 @@
+package dnsimple
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	cases := []struct {
+		value string
+		want  time.Time
+	}{
+		{"", time.Time{}},
+		{"2016-01-19T20:50:26Z", time.Date(2016, 1, 19, 20, 50, 26, 0, time.UTC)},
+		{"2016-02-07T14:46:29.142Z", time.Date(2016, 2, 7, 14, 46, 29, 142000000, time.UTC)},
+	}
+
+	for _, c := range cases {
+		got, err := parseTimestamp(c.value)
+		if err != nil {
+			t.Errorf("parseTimestamp(%q) returned error: %v", c.value, err)
+		}
+		if !got.Equal(c.want) {
+			t.Errorf("parseTimestamp(%q) = %v, want %v", c.value, got, c.want)
+		}
+	}
+
+	if _, err := parseTimestamp("2016-01-19"); err == nil {
+		t.Errorf("parseTimestamp() expected to return an error for a date")
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	cases := []struct {
+		value string
+		want  time.Time
+	}{
+		{"", time.Time{}},
+		{"2015-12-06", time.Date(2015, 12, 6, 0, 0, 0, 0, time.UTC)},
+		{"2020-06-18T18:54:17Z", time.Date(2020, 6, 18, 18, 54, 17, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		got, err := parseDate(c.value)
+		if err != nil {
+			t.Errorf("parseDate(%q) returned error: %v", c.value, err)
+		}
+		if !got.Equal(c.want) {
+			t.Errorf("parseDate(%q) = %v, want %v", c.value, got, c.want)
+		}
+	}
+
+	if _, err := parseDate("06/12/2015"); err == nil {
+		t.Errorf("parseDate() expected to return an error for an invalid date")
+	}
+}
+
+func TestDomain_TimeAccessors(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	serveFixture(t, "/v2/1010/domains", "/api/listDomains/success.http")
+
+	domainsResponse, err := client.Domains.ListDomains("1010", nil)
+	if err != nil {
+		t.Fatalf("Domains.ListDomains() returned error: %v", err)
+	}
+	domain := domainsResponse.Data[1]
+
+	createdAt, err := domain.CreatedAtTime()
+	if err != nil {
+		t.Fatalf("CreatedAtTime() returned error: %v", err)
+	}
+	if want := time.Date(2014, 12, 6, 15, 46, 52, 0, time.UTC); !createdAt.Equal(want) {
+		t.Errorf("CreatedAtTime() expected to be `%v`, got `%v`", want, createdAt)
+	}
+
+	expiresOn, err := domain.ExpiresOnTime()
+	if err != nil {
+		t.Fatalf("ExpiresOnTime() returned error: %v", err)
+	}
+	if want := time.Date(2015, 12, 6, 0, 0, 0, 0, time.UTC); !expiresOn.Equal(want) {
+		t.Errorf("ExpiresOnTime() expected to be `%v`, got `%v`", want, expiresOn)
+	}
+
+	expiresOn, err = domainsResponse.Data[0].ExpiresOnTime()
+	if err != nil {
+		t.Fatalf("ExpiresOnTime() returned error: %v", err)
+	}
+	if !expiresOn.IsZero() {
+		t.Errorf("ExpiresOnTime() expected to be zero for a hosted domain, got `%v`", expiresOn)
+	}
+
+	// The raw values are serialized unchanged.
+	data, err := json.Marshal(domain)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned error: %v", err)
+	}
+	decoded := Domain{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() returned error: %v", err)
+	}
+	if decoded.ExpiresOn != domain.ExpiresOn || decoded.CreatedAt != domain.CreatedAt {
+		t.Errorf("json.Marshal() expected to preserve the raw timestamps, got %+v", decoded)
+	}
+}
@@ -2,6 +2,8 @@ package dnsimple
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 )
 
 // TldsService handles communication with the Tld related
@@ -115,3 +117,94 @@ func (s *TldsService) GetTldExtendedAttributes(tld string) (*tldExtendedAttribut
 	tldResponse.HttpResponse = resp
 	return tldResponse, nil
 }
+
+// ExtendedAttributeError represents an extended attribute value
+// that doesn't match the definition of the TLD.
+type ExtendedAttributeError struct {
+	Name    string
+	Message string
+}
+
+// Error implements the error interface.
+func (e ExtendedAttributeError) Error() string {
+	return fmt.Sprintf("extended attribute %v %v", e.Name, e.Message)
+}
+
+// ExtendedAttributesError represents the list of invalid extended attributes
+// returned by ValidateExtendedAttributes.
+type ExtendedAttributesError struct {
+	Errors []ExtendedAttributeError
+}
+
+// Error implements the error interface.
+func (e *ExtendedAttributesError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "invalid extended attributes: " + strings.Join(messages, "; ")
+}
+
+// ValidateExtendedAttributes checks the extended attributes of a registration
+// or a transfer request against the definitions of the TLD, as returned by
+// TldsService.GetTldExtendedAttributes.
+//
+// The required attributes must be present, the values of the attributes with
+// options must be one of the options, and the attributes must be supported
+// by the TLD. It returns an *ExtendedAttributesError listing all the problems,
+// or nil if the attributes are valid.
+func ValidateExtendedAttributes(definitions []TldExtendedAttribute, values map[string]string) error {
+	var errs []ExtendedAttributeError
+	defined := map[string]bool{}
+
+	for _, definition := range definitions {
+		defined[definition.Name] = true
+
+		value, ok := values[definition.Name]
+		if !ok || value == "" {
+			if definition.Required {
+				errs = append(errs, ExtendedAttributeError{Name: definition.Name, Message: "is required"})
+			}
+			continue
+		}
+
+		if len(definition.Options) == 0 {
+			continue
+		}
+		allowed := make([]string, len(definition.Options))
+		for i, option := range definition.Options {
+			allowed[i] = option.Value
+		}
+		if !containsString(allowed, value) {
+			errs = append(errs, ExtendedAttributeError{
+				Name:    definition.Name,
+				Message: fmt.Sprintf("must be one of %v, got %q", strings.Join(allowed, ", "), value),
+			})
+		}
+	}
+
+	var unsupported []string
+	for name := range values {
+		if !defined[name] {
+			unsupported = append(unsupported, name)
+		}
+	}
+	sort.Strings(unsupported)
+	for _, name := range unsupported {
+		errs = append(errs, ExtendedAttributeError{Name: name, Message: "is not supported by the TLD"})
+	}
+
+	if len(errs) > 0 {
+		return &ExtendedAttributesError{Errors: errs}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
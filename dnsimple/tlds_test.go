@@ -132,3 +132,36 @@ func TestTldsService_GetTldExtendedAttributes(t *testing.T) {
 		t.Fatalf("Tlds.GetTldExtendedAttributes() returned Tld expected to be `%v`, got `%v`", want, got)
 	}
 }
+
+func TestValidateExtendedAttributes(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	serveFixture(t, "/v2/tlds/uk/extended_attributes", "/api/getTldExtendedAttributes/success.http")
+
+	tldResponse, err := client.Tlds.GetTldExtendedAttributes("uk")
+	if err != nil {
+		t.Fatalf("Tlds.GetTldExtendedAttributes() returned error: %v", err)
+	}
+	definitions := tldResponse.Data
+
+	valid := map[string]string{"registered_for": "Example Ltd", "uk_legal_type": "LTD", "uk_reg_co_no": "12345678"}
+	if err := ValidateExtendedAttributes(definitions, valid); err != nil {
+		t.Errorf("ValidateExtendedAttributes() returned error: %v", err)
+	}
+
+	invalid := map[string]string{"uk_legal_type": "CORP", "uk_reg_opt_out": "y", "us_nexus": "C11"}
+	err = ValidateExtendedAttributes(definitions, invalid)
+	attributesErr, ok := err.(*ExtendedAttributesError)
+	if !ok {
+		t.Fatalf("ValidateExtendedAttributes() expected to return an *ExtendedAttributesError, got %v", err)
+	}
+
+	var names []string
+	for _, e := range attributesErr.Errors {
+		names = append(names, e.Name)
+	}
+	if want := []string{"uk_legal_type", "registered_for", "us_nexus"}; !reflect.DeepEqual(want, names) {
+		t.Errorf("ValidateExtendedAttributes() errors expected to be `%v`, got `%v` (%v)", want, names, err)
+	}
+}
@@ -0,0 +1,288 @@
+package dnsimple
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// VanityNameServerStep identifies a step of the vanity name server workflows.
+type VanityNameServerStep string
+
+const (
+	// VanityNameServerStepDelegation is the step that gets the current delegation of the domain.
+	VanityNameServerStepDelegation = VanityNameServerStep("delegation")
+
+	// VanityNameServerStepEnable is the step that enables the vanity name servers.
+	VanityNameServerStepEnable = VanityNameServerStep("enable")
+
+	// VanityNameServerStepVerify is the step that checks the names and the addresses of the vanity name servers.
+	VanityNameServerStepVerify = VanityNameServerStep("verify")
+
+	// VanityNameServerStepDelegate is the step that changes the delegation of the domain.
+	VanityNameServerStepDelegate = VanityNameServerStep("delegate")
+
+	// VanityNameServerStepCheck is the step that checks the delegation after the change.
+	VanityNameServerStepCheck = VanityNameServerStep("check")
+
+	// VanityNameServerStepDisable is the step that disables the vanity name servers.
+	VanityNameServerStepDisable = VanityNameServerStep("disable")
+)
+
+// VanityNameServerError represents a failure of a step of the vanity name server workflows.
+type VanityNameServerError struct {
+	Domain string
+	Step   VanityNameServerStep
+	Err    error
+
+	// The error returned while rolling back the previous steps, if any.
+	// When set, the domain may be left in an inconsistent state.
+	RollbackErr error
+}
+
+// Error implements the error interface.
+func (e *VanityNameServerError) Error() string {
+	s := fmt.Sprintf("vanity name servers for %v: %v: %v", e.Domain, e.Step, e.Err)
+	if e.RollbackErr != nil {
+		s += fmt.Sprintf(" (rollback failed: %v)", e.RollbackErr)
+	}
+	return s
+}
+
+// Unwrap returns the underlying error.
+func (e *VanityNameServerError) Unwrap() error {
+	return e.Err
+}
+
+// VanityNameServerResult represents the outcome of a vanity name server workflow for a domain.
+type VanityNameServerResult struct {
+	Domain string
+
+	// The vanity name servers returned when they were enabled.
+	NameServers []VanityNameServer
+
+	// The delegation before and after the workflow.
+	PreviousDelegation Delegation
+	Delegation         Delegation
+
+	// Set to true when a step failed and the previous steps were reverted.
+	RolledBack bool
+
+	// The error returned for this domain, if any. See VanityNameServerError.
+	Err error
+}
+
+// EnableDomainVanityNameServers enables the vanity name servers of a domain
+// and delegates the domain to them.
+//
+// The vanity name servers returned by the API are checked to have a valid
+// IPv4 and IPv6 address, and the delegation is read back once changed.
+// When a step fails, the delegation is restored and the vanity name servers
+// are disabled again, unless they were already in use. A failure is returned
+// as a *VanityNameServerError, and reported in the result.
+func EnableDomainVanityNameServers(c *Client, accountID string, domainName string) (*VanityNameServerResult, error) {
+	result := &VanityNameServerResult{Domain: domainName}
+	result.Err = enableDomainVanityNameServers(c, accountID, result)
+	return result, result.Err
+}
+
+func enableDomainVanityNameServers(c *Client, accountID string, result *VanityNameServerResult) error {
+	domainName := result.Domain
+
+	delegationResponse, err := c.Registrar.GetDomainDelegation(accountID, domainName)
+	if err != nil {
+		return &VanityNameServerError{Domain: domainName, Step: VanityNameServerStepDelegation, Err: err}
+	}
+	result.PreviousDelegation = *delegationResponse.Data
+	result.Delegation = result.PreviousDelegation
+
+	vanityResponse, err := c.VanityNameServers.EnableVanityNameServers(accountID, domainName)
+	if err != nil {
+		return &VanityNameServerError{Domain: domainName, Step: VanityNameServerStepEnable, Err: err}
+	}
+	result.NameServers = vanityResponse.Data
+
+	vanityDelegation := make(Delegation, len(result.NameServers))
+	for i, nameServer := range result.NameServers {
+		vanityDelegation[i] = nameServer.Name
+	}
+	// When the domain is already delegated to the vanity name servers,
+	// they were enabled before the workflow and must not be disabled on failure.
+	alreadyEnabled := sameDelegation(result.PreviousDelegation, vanityDelegation)
+
+	delegated := false
+	fail := func(step VanityNameServerStep, err error) error {
+		vanityErr := &VanityNameServerError{Domain: domainName, Step: step, Err: err}
+		if delegated {
+			if _, err := c.Registrar.ChangeDomainDelegation(accountID, domainName, &result.PreviousDelegation); err != nil {
+				vanityErr.RollbackErr = err
+				return vanityErr
+			}
+			result.Delegation = result.PreviousDelegation
+		}
+		if !alreadyEnabled {
+			if _, err := c.VanityNameServers.DisableVanityNameServers(accountID, domainName); err != nil {
+				vanityErr.RollbackErr = err
+				return vanityErr
+			}
+		}
+		result.RolledBack = true
+		return vanityErr
+	}
+
+	if err := verifyVanityNameServers(result.NameServers); err != nil {
+		return fail(VanityNameServerStepVerify, err)
+	}
+
+	// A failed request may still have changed the delegation, so it's always restored.
+	delegated = true
+	if _, err := c.Registrar.ChangeDomainDelegationToVanity(accountID, domainName, &vanityDelegation); err != nil {
+		return fail(VanityNameServerStepDelegate, err)
+	}
+
+	delegationResponse, err = c.Registrar.GetDomainDelegation(accountID, domainName)
+	if err != nil {
+		return fail(VanityNameServerStepCheck, err)
+	}
+	result.Delegation = *delegationResponse.Data
+	if !sameDelegation(result.Delegation, vanityDelegation) {
+		return fail(VanityNameServerStepCheck, fmt.Errorf("domain is delegated to %v", strings.Join(result.Delegation, ", ")))
+	}
+
+	return nil
+}
+
+// DisableDomainVanityNameServers delegates a domain back to the DNSimple
+// name servers and disables its vanity name servers.
+//
+// When the vanity name servers can't be disabled, the domain is delegated
+// to them again. A failure is returned as a *VanityNameServerError,
+// and reported in the result.
+func DisableDomainVanityNameServers(c *Client, accountID string, domainName string) (*VanityNameServerResult, error) {
+	result := &VanityNameServerResult{Domain: domainName}
+	result.Err = disableDomainVanityNameServers(c, accountID, result)
+	return result, result.Err
+}
+
+func disableDomainVanityNameServers(c *Client, accountID string, result *VanityNameServerResult) error {
+	domainName := result.Domain
+
+	delegationResponse, err := c.Registrar.GetDomainDelegation(accountID, domainName)
+	if err != nil {
+		return &VanityNameServerError{Domain: domainName, Step: VanityNameServerStepDelegation, Err: err}
+	}
+	result.PreviousDelegation = *delegationResponse.Data
+	result.Delegation = result.PreviousDelegation
+
+	if _, err := c.Registrar.ChangeDomainDelegationFromVanity(accountID, domainName); err != nil {
+		return &VanityNameServerError{Domain: domainName, Step: VanityNameServerStepDelegate, Err: err}
+	}
+
+	if _, err := c.VanityNameServers.DisableVanityNameServers(accountID, domainName); err != nil {
+		vanityErr := &VanityNameServerError{Domain: domainName, Step: VanityNameServerStepDisable, Err: err}
+		if _, err := c.Registrar.ChangeDomainDelegationToVanity(accountID, domainName, &result.PreviousDelegation); err != nil {
+			vanityErr.RollbackErr = err
+			return vanityErr
+		}
+		result.RolledBack = true
+		return vanityErr
+	}
+
+	delegationResponse, err = c.Registrar.GetDomainDelegation(accountID, domainName)
+	if err != nil {
+		return &VanityNameServerError{Domain: domainName, Step: VanityNameServerStepCheck, Err: err}
+	}
+	result.Delegation = *delegationResponse.Data
+
+	return nil
+}
+
+// EnableDomainsVanityNameServers runs EnableDomainVanityNameServers for each domain
+// using a bounded pool of workers.
+//
+// The results are returned in the same order as the domains. The returned error
+// is the first error encountered in input order, or the context error if the
+// operation was cancelled.
+func EnableDomainsVanityNameServers(ctx context.Context, c *Client, accountID string, domainNames []string, options *BulkOptions) ([]VanityNameServerResult, error) {
+	return runVanityNameServersBulk(ctx, domainNames, options, func(result *VanityNameServerResult) error {
+		return enableDomainVanityNameServers(c, accountID, result)
+	})
+}
+
+// DisableDomainsVanityNameServers runs DisableDomainVanityNameServers for each domain
+// using a bounded pool of workers.
+//
+// The results are returned in the same order as the domains. The returned error
+// is the first error encountered in input order, or the context error if the
+// operation was cancelled.
+func DisableDomainsVanityNameServers(ctx context.Context, c *Client, accountID string, domainNames []string, options *BulkOptions) ([]VanityNameServerResult, error) {
+	return runVanityNameServersBulk(ctx, domainNames, options, func(result *VanityNameServerResult) error {
+		return disableDomainVanityNameServers(c, accountID, result)
+	})
+}
+
+func runVanityNameServersBulk(ctx context.Context, domainNames []string, options *BulkOptions, workflow func(result *VanityNameServerResult) error) ([]VanityNameServerResult, error) {
+	results := make([]VanityNameServerResult, len(domainNames))
+
+	errs, err := runBulk(ctx, len(domainNames), options, func(i int) (*http.Response, error) {
+		results[i] = VanityNameServerResult{Domain: domainNames[i]}
+		err := workflow(&results[i])
+
+		// A rate limited workflow is retried from the start, which is only
+		// safe when nothing was changed or the changes were rolled back.
+		if vanityErr, ok := err.(*VanityNameServerError); ok && vanityErr.RollbackErr == nil && isRateLimited(vanityErr.Err) {
+			return nil, vanityErr.Err
+		}
+		return nil, err
+	})
+
+	for i := range results {
+		results[i].Domain = domainNames[i]
+		results[i].Err = errs[i]
+	}
+	return results, err
+}
+
+// verifyVanityNameServers checks that there is at least one vanity name server,
+// and that each one has a name, a valid IPv4 and a valid IPv6 address.
+func verifyVanityNameServers(nameServers []VanityNameServer) error {
+	if len(nameServers) == 0 {
+		return fmt.Errorf("no vanity name servers returned")
+	}
+
+	for _, nameServer := range nameServers {
+		if nameServer.Name == "" {
+			return fmt.Errorf("vanity name server %v has no name", nameServer.ID)
+		}
+		if ip := net.ParseIP(nameServer.IPv4); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("vanity name server %v has an invalid IPv4 address %q", nameServer.Name, nameServer.IPv4)
+		}
+		if ip := net.ParseIP(nameServer.IPv6); ip == nil || ip.To4() != nil {
+			return fmt.Errorf("vanity name server %v has an invalid IPv6 address %q", nameServer.Name, nameServer.IPv6)
+		}
+	}
+	return nil
+}
+
+// sameDelegation returns true if the delegations contain the same name servers, in any order.
+func sameDelegation(a, b Delegation) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	names := map[string]int{}
+	for _, name := range a {
+		names[normalizeHostName(name)]++
+	}
+	for _, name := range b {
+		names[normalizeHostName(name)]--
+	}
+	for _, count := range names {
+		if count != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,255 @@
+package dnsimple
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// vanityFixtures simulates the delegation and the vanity name servers of the domains of account 1010.
+type vanityFixtures struct {
+	mu          sync.Mutex
+	delegations map[string]Delegation
+	requests    []string
+
+	// The requests that fail, as "METHOD /path".
+	failures map[string]bool
+
+	// The IPv6 address returned for the vanity name servers.
+	ipv6 string
+}
+
+func setupVanityFixtures(t *testing.T, domainNames ...string) *vanityFixtures {
+	f := &vanityFixtures{delegations: map[string]Delegation{}, failures: map[string]bool{}, ipv6: "::1"}
+
+	for _, domainName := range domainNames {
+		domainName := domainName
+		f.delegations[domainName] = Delegation{"ns1.dnsimple.com", "ns2.dnsimple.com"}
+		vanity := Delegation{"ns1." + domainName, "ns2." + domainName}
+
+		handle := func(path string, fn func(r *http.Request) interface{}) {
+			mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+				f.mu.Lock()
+				defer f.mu.Unlock()
+
+				request := r.Method + " " + strings.TrimPrefix(r.URL.Path, "/v2/1010")
+				f.requests = append(f.requests, request)
+				if f.failures[request] {
+					httpResponse := httpResponseFixture(t, "/api/validation-error.http")
+					w.WriteHeader(httpResponse.StatusCode)
+					io.Copy(w, httpResponse.Body)
+					return
+				}
+
+				data := fn(r)
+				if data == nil {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+			})
+		}
+
+		handle("/v2/1010/registrar/domains/"+domainName+"/delegation", func(r *http.Request) interface{} {
+			if r.Method == "PUT" {
+				var delegation Delegation
+				json.NewDecoder(r.Body).Decode(&delegation)
+				f.delegations[domainName] = delegation
+			}
+			return f.delegations[domainName]
+		})
+		handle("/v2/1010/registrar/domains/"+domainName+"/delegation/vanity", func(r *http.Request) interface{} {
+			if r.Method == "DELETE" {
+				f.delegations[domainName] = Delegation{"ns1.dnsimple.com", "ns2.dnsimple.com"}
+				return nil
+			}
+			var delegation Delegation
+			json.NewDecoder(r.Body).Decode(&delegation)
+			f.delegations[domainName] = delegation
+			return f.vanityNameServers(vanity)
+		})
+		handle("/v2/1010/vanity/"+domainName, func(r *http.Request) interface{} {
+			if r.Method == "DELETE" {
+				return nil
+			}
+			return f.vanityNameServers(vanity)
+		})
+	}
+
+	return f
+}
+
+func (f *vanityFixtures) vanityNameServers(names Delegation) []VanityNameServer {
+	var nameServers []VanityNameServer
+	for i, name := range names {
+		nameServers = append(nameServers, VanityNameServer{ID: int64(i + 1), Name: name, IPv4: "127.0.0.1", IPv6: f.ipv6})
+	}
+	return nameServers
+}
+
+func (f *vanityFixtures) fail(request string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[request] = true
+}
+
+func TestEnableDomainVanityNameServers(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupVanityFixtures(t, "example.com")
+
+	result, err := EnableDomainVanityNameServers(client, "1010", "example.com")
+	if err != nil {
+		t.Fatalf("EnableDomainVanityNameServers() returned error: %v", err)
+	}
+
+	if want, got := (Delegation{"ns1.example.com", "ns2.example.com"}), result.Delegation; !reflect.DeepEqual(want, got) {
+		t.Errorf("EnableDomainVanityNameServers() returned Delegation expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := (Delegation{"ns1.dnsimple.com", "ns2.dnsimple.com"}), result.PreviousDelegation; !reflect.DeepEqual(want, got) {
+		t.Errorf("EnableDomainVanityNameServers() returned PreviousDelegation expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := 2, len(result.NameServers); want != got {
+		t.Errorf("EnableDomainVanityNameServers() returned %v name servers, expected %v", got, want)
+	}
+
+	want := []string{
+		"GET /registrar/domains/example.com/delegation",
+		"PUT /vanity/example.com",
+		"PUT /registrar/domains/example.com/delegation/vanity",
+		"GET /registrar/domains/example.com/delegation",
+	}
+	if !reflect.DeepEqual(f.requests, want) {
+		t.Errorf("EnableDomainVanityNameServers() requests expected to be %v, got %v", want, f.requests)
+	}
+}
+
+func TestEnableDomainVanityNameServers_RollbackDelegation(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupVanityFixtures(t, "example.com")
+	f.fail("PUT /registrar/domains/example.com/delegation/vanity")
+
+	result, err := EnableDomainVanityNameServers(client, "1010", "example.com")
+	vanityErr, ok := err.(*VanityNameServerError)
+	if !ok {
+		t.Fatalf("EnableDomainVanityNameServers() expected to return a *VanityNameServerError, got `%v`", err)
+	}
+
+	if want, got := VanityNameServerStepDelegate, vanityErr.Step; want != got {
+		t.Errorf("EnableDomainVanityNameServers() returned Step expected to be `%v`, got `%v`", want, got)
+	}
+	if !result.RolledBack {
+		t.Errorf("EnableDomainVanityNameServers() expected to roll back")
+	}
+
+	want := []string{
+		"GET /registrar/domains/example.com/delegation",
+		"PUT /vanity/example.com",
+		"PUT /registrar/domains/example.com/delegation/vanity",
+		"PUT /registrar/domains/example.com/delegation",
+		"DELETE /vanity/example.com",
+	}
+	if !reflect.DeepEqual(f.requests, want) {
+		t.Errorf("EnableDomainVanityNameServers() requests expected to be %v, got %v", want, f.requests)
+	}
+}
+
+func TestEnableDomainVanityNameServers_InvalidAddress(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupVanityFixtures(t, "example.com")
+	f.ipv6 = "127.0.0.2"
+
+	result, err := EnableDomainVanityNameServers(client, "1010", "example.com")
+	vanityErr, ok := err.(*VanityNameServerError)
+	if !ok {
+		t.Fatalf("EnableDomainVanityNameServers() expected to return a *VanityNameServerError, got `%v`", err)
+	}
+
+	if want, got := VanityNameServerStepVerify, vanityErr.Step; want != got {
+		t.Errorf("EnableDomainVanityNameServers() returned Step expected to be `%v`, got `%v`", want, got)
+	}
+	if !result.RolledBack {
+		t.Errorf("EnableDomainVanityNameServers() expected to roll back")
+	}
+	if want, got := "DELETE /vanity/example.com", f.requests[len(f.requests)-1]; want != got {
+		t.Errorf("EnableDomainVanityNameServers() last request expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestDisableDomainVanityNameServers(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupVanityFixtures(t, "example.com")
+	f.delegations["example.com"] = Delegation{"ns1.example.com", "ns2.example.com"}
+
+	result, err := DisableDomainVanityNameServers(client, "1010", "example.com")
+	if err != nil {
+		t.Fatalf("DisableDomainVanityNameServers() returned error: %v", err)
+	}
+
+	if want, got := (Delegation{"ns1.dnsimple.com", "ns2.dnsimple.com"}), result.Delegation; !reflect.DeepEqual(want, got) {
+		t.Errorf("DisableDomainVanityNameServers() returned Delegation expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestDisableDomainVanityNameServers_Rollback(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupVanityFixtures(t, "example.com")
+	f.delegations["example.com"] = Delegation{"ns1.example.com", "ns2.example.com"}
+	f.fail("DELETE /vanity/example.com")
+
+	result, err := DisableDomainVanityNameServers(client, "1010", "example.com")
+	if err == nil {
+		t.Fatalf("DisableDomainVanityNameServers() expected to return an error")
+	}
+
+	if !result.RolledBack {
+		t.Errorf("DisableDomainVanityNameServers() expected to roll back")
+	}
+	if want, got := (Delegation{"ns1.example.com", "ns2.example.com"}), f.delegations["example.com"]; !reflect.DeepEqual(want, got) {
+		t.Errorf("DisableDomainVanityNameServers() expected the delegation to be restored to `%v`, got `%v`", want, got)
+	}
+}
+
+func TestEnableDomainsVanityNameServers(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	var domainNames []string
+	for i := 0; i < 5; i++ {
+		domainNames = append(domainNames, fmt.Sprintf("example%d.com", i))
+	}
+	f := setupVanityFixtures(t, domainNames...)
+	f.fail("PUT /vanity/example3.com")
+
+	results, err := EnableDomainsVanityNameServers(context.Background(), client, "1010", domainNames, &BulkOptions{Concurrency: 2})
+	if err == nil {
+		t.Fatalf("EnableDomainsVanityNameServers() expected to return an error")
+	}
+
+	for i, result := range results {
+		if want, got := domainNames[i], result.Domain; want != got {
+			t.Errorf("EnableDomainsVanityNameServers() result %v Domain expected to be `%v`, got `%v`", i, want, got)
+		}
+		if failed := i == 3; failed != (result.Err != nil) {
+			t.Errorf("EnableDomainsVanityNameServers() result %v returned error `%v`", i, result.Err)
+		}
+	}
+	if want, got := (Delegation{"ns1.example0.com", "ns2.example0.com"}), f.delegations["example0.com"]; !reflect.DeepEqual(want, got) {
+		t.Errorf("EnableDomainsVanityNameServers() expected the delegation to be `%v`, got `%v`", want, got)
+	}
+}
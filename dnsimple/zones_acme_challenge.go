@@ -0,0 +1,228 @@
+package dnsimple
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultACMEChallengeTTL          = 60
+	defaultACMEChallengePollInterval = 5 * time.Second
+)
+
+// ACMEChallengeRecord returns the name and the content of the TXT record that
+// solves the DNS-01 challenge of the domain with the key authorization (RFC 8555, section 8.4).
+// The challenge of a wildcard domain is solved by the record of its base domain.
+func ACMEChallengeRecord(domainName string, keyAuthorization string) (fqdn string, value string) {
+	domainName = strings.TrimSuffix(strings.TrimPrefix(domainName, "*."), ".")
+	digest := sha256.Sum256([]byte(keyAuthorization))
+	return "_acme-challenge." + domainName, base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// ACMEChallengeSolver solves ACME DNS-01 challenges with TXT records in the zones of an account.
+//
+// The solver keeps track of the records it creates, and CleanUp only deletes
+// those records, even when Present failed after creating them.
+type ACMEChallengeSolver struct {
+	client    *Client
+	accountID string
+
+	// The TTL of the challenge records. Defaults to 60 seconds.
+	TTL int
+
+	// The interval between two checks of the distribution of a challenge record.
+	// Defaults to 5 seconds.
+	PollInterval time.Duration
+
+	mu      sync.Mutex
+	zones   map[string]string
+	records map[string][]acmeChallengeRecord
+}
+
+// acmeChallengeRecord represents a challenge record created by an ACMEChallengeSolver.
+type acmeChallengeRecord struct {
+	zoneName string
+	recordID int64
+}
+
+// NewACMEChallengeSolver returns an ACMEChallengeSolver for the zones of the account.
+func NewACMEChallengeSolver(c *Client, accountID string) *ACMEChallengeSolver {
+	return &ACMEChallengeSolver{
+		client:       c,
+		accountID:    accountID,
+		TTL:          defaultACMEChallengeTTL,
+		PollInterval: defaultACMEChallengePollInterval,
+		zones:        map[string]string{},
+		records:      map[string][]acmeChallengeRecord{},
+	}
+}
+
+// Present creates the TXT record with the value, and waits until the record
+// is distributed to all the DNSimple name servers, or the context is done.
+// See ACMEChallengeRecord to compute the name and the value of the record.
+func (s *ACMEChallengeSolver) Present(ctx context.Context, fqdn string, value string) error {
+	fqdn = strings.ToLower(strings.TrimSuffix(fqdn, "."))
+
+	zoneName, err := s.FindZone(fqdn)
+	if err != nil {
+		return err
+	}
+
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = defaultACMEChallengeTTL
+	}
+	recordResponse, err := s.client.Zones.CreateRecord(s.accountID, zoneName, ZoneRecord{
+		Type:    "TXT",
+		Name:    strings.TrimSuffix(strings.TrimSuffix(fqdn, zoneName), "."),
+		Content: value,
+		TTL:     ttl,
+	})
+	if err != nil {
+		return fmt.Errorf("creating challenge record %v: %v", fqdn, err)
+	}
+
+	record := acmeChallengeRecord{zoneName: zoneName, recordID: recordResponse.Data.ID}
+	s.mu.Lock()
+	s.records[acmeChallengeKey(fqdn, value)] = append(s.records[acmeChallengeKey(fqdn, value)], record)
+	s.mu.Unlock()
+
+	if err := s.waitForDistribution(ctx, record); err != nil {
+		return fmt.Errorf("waiting for the distribution of challenge record %v: %v", fqdn, err)
+	}
+	return nil
+}
+
+// CleanUp deletes the TXT records created by Present for the name and the value.
+// The records that can't be deleted are kept, and deleted by the next CleanUp.
+func (s *ACMEChallengeSolver) CleanUp(fqdn string, value string) error {
+	return s.cleanUp(acmeChallengeKey(strings.ToLower(strings.TrimSuffix(fqdn, ".")), value))
+}
+
+// CleanUpAll deletes all the TXT records created by Present and not deleted yet.
+func (s *ACMEChallengeSolver) CleanUpAll() error {
+	s.mu.Lock()
+	var keys []string
+	for key := range s.records {
+		keys = append(keys, key)
+	}
+	s.mu.Unlock()
+
+	var firstErr error
+	for _, key := range keys {
+		if err := s.cleanUp(key); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (s *ACMEChallengeSolver) cleanUp(key string) error {
+	s.mu.Lock()
+	records := s.records[key]
+	delete(s.records, key)
+	s.mu.Unlock()
+
+	var kept []acmeChallengeRecord
+	var firstErr error
+	for _, record := range records {
+		_, err := s.client.Zones.DeleteRecord(s.accountID, record.zoneName, record.recordID)
+		if err != nil && !hasStatusCode(err, http.StatusNotFound) {
+			kept = append(kept, record)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("deleting challenge record %v: %v", record.recordID, err)
+			}
+		}
+	}
+
+	if len(kept) > 0 {
+		s.mu.Lock()
+		s.records[key] = append(s.records[key], kept...)
+		s.mu.Unlock()
+	}
+	return firstErr
+}
+
+// FindZone returns the name of the zone of the account the name belongs to,
+// walking up the labels of the name until a zone is found.
+func (s *ACMEChallengeSolver) FindZone(fqdn string) (string, error) {
+	fqdn = strings.ToLower(strings.TrimSuffix(fqdn, "."))
+
+	s.mu.Lock()
+	zoneName, ok := s.zones[fqdn]
+	s.mu.Unlock()
+	if ok {
+		return zoneName, nil
+	}
+
+	for name := fqdn; strings.Contains(name, "."); name = name[strings.Index(name, ".")+1:] {
+		found, err := s.zoneExists(name)
+		if err != nil {
+			return "", fmt.Errorf("finding the zone of %v: %v", fqdn, err)
+		}
+		if found {
+			s.mu.Lock()
+			s.zones[fqdn] = name
+			s.mu.Unlock()
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("no zone found for %v", fqdn)
+}
+
+// zoneExists returns true if the account has a zone with the name.
+func (s *ACMEChallengeSolver) zoneExists(name string) (bool, error) {
+	found := false
+	err := eachPage(func(options ListOptions) (*Pagination, error) {
+		zonesResponse, err := s.client.Zones.ListZones(s.accountID, &ZoneListOptions{NameLike: name, ListOptions: options})
+		if err != nil {
+			return nil, err
+		}
+		for _, zone := range zonesResponse.Data {
+			if strings.EqualFold(zone.Name, name) {
+				found = true
+			}
+		}
+		return zonesResponse.Pagination, nil
+	})
+	return found, err
+}
+
+// waitForDistribution polls the distribution of the record until it's distributed.
+// The API returns a 504 when a name server can't be queried, which is retried.
+func (s *ACMEChallengeSolver) waitForDistribution(ctx context.Context, record acmeChallengeRecord) error {
+	interval := s.PollInterval
+	if interval <= 0 {
+		interval = defaultACMEChallengePollInterval
+	}
+
+	for {
+		distributionResponse, err := s.client.Zones.CheckZoneRecordDistribution(s.accountID, record.zoneName, record.recordID)
+		switch {
+		case err == nil && distributionResponse.Data.Distributed:
+			return nil
+		case err != nil && !hasStatusCode(err, http.StatusGatewayTimeout):
+			return err
+		}
+
+		if err := sleep(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+func acmeChallengeKey(fqdn string, value string) string {
+	return fqdn + " " + value
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	errorResponse, ok := err.(*ErrorResponse)
+	return ok && errorResponse.HttpResponse.StatusCode == statusCode
+}
@@ -0,0 +1,248 @@
+package dnsimple
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// acmeChallengeFixtures simulates the zones example.com and sub.example.com,
+// among zones with similar names.
+type acmeChallengeFixtures struct {
+	mu      sync.Mutex
+	nextID  int64
+	records map[int64]ZoneRecord
+
+	// The distribution states returned by the next checks, by record ID:
+	// http.StatusOK for a record not distributed yet, any other status for a timeout.
+	// A record is distributed once its states are exhausted.
+	distributions map[int64][]int
+
+	// The status codes returned by the next record creations and deletions.
+	createStatuses []int
+	deleteStatuses []int
+
+	requests []string
+}
+
+func setupACMEChallengeFixtures(t *testing.T) *acmeChallengeFixtures {
+	f := &acmeChallengeFixtures{nextID: 1, records: map[int64]ZoneRecord{}, distributions: map[int64][]int{}}
+	zones := []Zone{{ID: 1, Name: "example.com"}, {ID: 2, Name: "api.sub.example.com"}, {ID: 3, Name: "www-sub.example.com"}, {ID: 4, Name: "sub.example.com"}}
+
+	next := func(statuses *[]int) int {
+		if len(*statuses) == 0 {
+			return 0
+		}
+		status := (*statuses)[0]
+		*statuses = (*statuses)[1:]
+		return status
+	}
+
+	mux.HandleFunc("/v2/1010/zones", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		nameLike := r.URL.Query().Get("name_like")
+		f.requests = append(f.requests, "LIST "+nameLike)
+
+		matching := []Zone{}
+		for _, zone := range zones {
+			if strings.Contains(zone.Name, nameLike) {
+				matching = append(matching, zone)
+			}
+		}
+		writeData(w, r, http.StatusOK, matching)
+	})
+
+	mux.HandleFunc("/v2/1010/zones/", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v2/1010/zones/"), "/")
+		zoneName := parts[0]
+
+		switch {
+		case r.Method == "POST" && len(parts) == 2:
+			var record ZoneRecord
+			json.NewDecoder(r.Body).Decode(&record)
+			f.requests = append(f.requests, "CREATE "+zoneName+" "+record.Name)
+			if status := next(&f.createStatuses); status != 0 {
+				w.WriteHeader(status)
+				w.Write([]byte(`{"message":"error"}`))
+				return
+			}
+			record.ID, record.ZoneID = f.nextID, zoneName
+			f.nextID++
+			f.records[record.ID] = record
+			writeData(w, r, http.StatusCreated, record)
+		case r.Method == "DELETE" && len(parts) == 3:
+			f.requests = append(f.requests, "DELETE "+zoneName+" "+parts[2])
+			if status := next(&f.deleteStatuses); status != 0 {
+				w.WriteHeader(status)
+				w.Write([]byte(`{"message":"error"}`))
+				return
+			}
+			id, _ := strconv.ParseInt(parts[2], 10, 64)
+			if _, ok := f.records[id]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"message":"Record not found"}`))
+				return
+			}
+			delete(f.records, id)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "GET" && len(parts) == 4 && parts[3] == "distribution":
+			id, _ := strconv.ParseInt(parts[2], 10, 64)
+			fixture := "/api/checkZoneRecordDistribution/success.http"
+			if distributions := f.distributions[id]; len(distributions) > 0 {
+				fixture = "/api/checkZoneRecordDistribution/error.http"
+				if next(&distributions) == http.StatusOK {
+					fixture = "/api/checkZoneRecordDistribution/failure.http"
+				}
+				f.distributions[id] = distributions
+			}
+			httpResponse := httpResponseFixture(t, fixture)
+
+			w.WriteHeader(httpResponse.StatusCode)
+			io.Copy(w, httpResponse.Body)
+		default:
+			t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+		}
+	})
+
+	return f
+}
+
+func TestACMEChallengeRecord(t *testing.T) {
+	fqdn, value := ACMEChallengeRecord("*.example.com.", "evaGxfADs6pSRb2LAv9IZf17Dt3juxGJ-PCt92wr-oA.nysa1Xu0N7EEs1ntqTvbsQ3WqLj4IbU9JEyOtUSX8IM")
+
+	if want, got := "_acme-challenge.example.com", fqdn; want != got {
+		t.Errorf("ACMEChallengeRecord() returned fqdn expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := "SOTmsn7Ya3IyDcvQo5EwS4UwAur6dNENXcYxA0EQsqw", value; want != got {
+		t.Errorf("ACMEChallengeRecord() returned value expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestACMEChallengeSolver_FindZone(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupACMEChallengeFixtures(t)
+	solver := NewACMEChallengeSolver(client, "1010")
+
+	zoneName, err := solver.FindZone("_acme-challenge.www.sub.example.com.")
+	if err != nil {
+		t.Fatalf("FindZone() returned error: %v", err)
+	}
+	if want, got := "sub.example.com", zoneName; want != got {
+		t.Errorf("FindZone() returned `%v`, expected `%v`", got, want)
+	}
+
+	// The zone is on the second page of the zones like sub.example.com, and is cached.
+	solver.FindZone("_acme-challenge.www.sub.example.com")
+	if want, got := []string{"LIST _acme-challenge.www.sub.example.com", "LIST www.sub.example.com", "LIST sub.example.com", "LIST sub.example.com"}, f.requests; !reflect.DeepEqual(want, got) {
+		t.Errorf("FindZone() requests expected to be `%v`, got `%v`", want, got)
+	}
+
+	if _, err := solver.FindZone("_acme-challenge.example.org"); err == nil {
+		t.Errorf("FindZone() expected to return an error for an unknown zone")
+	}
+}
+
+func TestACMEChallengeSolver_PresentAndCleanUp(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupACMEChallengeFixtures(t)
+	f.distributions[1] = []int{http.StatusOK, http.StatusGatewayTimeout}
+
+	solver := NewACMEChallengeSolver(client, "1010")
+	solver.PollInterval = time.Millisecond
+
+	if err := solver.Present(context.Background(), "_acme-challenge.www.example.com", "value"); err != nil {
+		t.Fatalf("Present() returned error: %v", err)
+	}
+
+	record := f.records[1]
+	want := ZoneRecord{ID: 1, ZoneID: "example.com", Type: "TXT", Name: "_acme-challenge.www", Content: "value", TTL: 60}
+	if !reflect.DeepEqual(want, record) {
+		t.Errorf("Present() created record `%+v`, expected `%+v`", record, want)
+	}
+	if want, got := 0, len(f.distributions[1]); want != got {
+		t.Errorf("Present() expected to wait for the distribution")
+	}
+
+	// A record that isn't created by the solver is left untouched.
+	f.records[99] = ZoneRecord{ID: 99, Type: "TXT", Name: "_acme-challenge.www", Content: "value"}
+
+	if err := solver.CleanUp("_acme-challenge.www.example.com.", "value"); err != nil {
+		t.Fatalf("CleanUp() returned error: %v", err)
+	}
+	if err := solver.CleanUp("_acme-challenge.www.example.com", "value"); err != nil {
+		t.Fatalf("CleanUp() returned error: %v", err)
+	}
+
+	if _, ok := f.records[1]; ok {
+		t.Errorf("CleanUp() expected to delete the record")
+	}
+	if _, ok := f.records[99]; !ok {
+		t.Errorf("CleanUp() expected to keep the records not created by the solver")
+	}
+	if want, got := 1, strings.Count(strings.Join(f.requests, "\n"), "DELETE"); want != got {
+		t.Errorf("CleanUp() expected %v deletion, got %v", want, got)
+	}
+}
+
+func TestACMEChallengeSolver_PartialFailures(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupACMEChallengeFixtures(t)
+	f.distributions[1] = []int{http.StatusOK}
+	f.createStatuses = []int{0, http.StatusBadRequest}
+
+	solver := NewACMEChallengeSolver(client, "1010")
+	solver.PollInterval = time.Millisecond
+
+	// The record is created, but the context is done before the distribution.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := solver.Present(ctx, "_acme-challenge.example.com", "first"); err == nil {
+		t.Errorf("Present() expected to return an error")
+	}
+
+	// The record is not created.
+	if err := solver.Present(context.Background(), "_acme-challenge.example.com", "second"); err == nil {
+		t.Errorf("Present() expected to return an error")
+	}
+
+	// The record is deleted on the next clean up when the deletion fails.
+	f.deleteStatuses = []int{http.StatusInternalServerError}
+	if err := solver.CleanUpAll(); err == nil {
+		t.Errorf("CleanUpAll() expected to return an error")
+	}
+	if err := solver.CleanUpAll(); err != nil {
+		t.Errorf("CleanUpAll() returned error: %v", err)
+	}
+
+	if want, got := 0, len(f.records); want != got {
+		t.Errorf("CleanUpAll() expected to delete all the records, %v left", got)
+	}
+
+	var deletions []string
+	for _, request := range f.requests {
+		if strings.HasPrefix(request, "DELETE") {
+			deletions = append(deletions, request)
+		}
+	}
+	if want, got := []string{"DELETE example.com 1", "DELETE example.com 1"}, deletions; !reflect.DeepEqual(want, got) {
+		t.Errorf("CleanUpAll() requests expected to be `%v`, got `%v`", want, got)
+	}
+}
@@ -0,0 +1,308 @@
+package dnsimple
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// MailExchanger represents a mail server of a domain.
+type MailExchanger struct {
+	Host     string
+	Priority int
+}
+
+// EmailDNS represents the email records of a zone: the mail servers, the SPF policy,
+// the DKIM keys and the DMARC policy.
+//
+// A nil or empty field leaves the corresponding records of the zone untouched.
+type EmailDNS struct {
+	MX    []MailExchanger
+	SPF   *SPFRecord
+	DKIM  []*DKIMKey
+	DMARC *DMARCRecord
+
+	// The TTL of the records. Zero means the default TTL of the zone.
+	TTL int
+}
+
+// Records returns the zone records of the configuration.
+func (e *EmailDNS) Records() []ZoneRecord {
+	var records []ZoneRecord
+	for _, mx := range e.MX {
+		records = append(records, ZoneRecord{Type: "MX", Name: "", Content: normalizeHostName(mx.Host), Priority: mx.Priority, TTL: e.TTL})
+	}
+	if e.SPF != nil {
+		records = append(records, ZoneRecord{Type: "TXT", Name: "", Content: splitTXTContent(e.SPF.String()), TTL: e.TTL})
+	}
+	for _, key := range e.DKIM {
+		records = append(records, key.ZoneRecord(e.TTL))
+	}
+	if e.DMARC != nil {
+		records = append(records, ZoneRecord{Type: "TXT", Name: "_dmarc", Content: splitTXTContent(e.DMARC.String()), TTL: e.TTL})
+	}
+	return records
+}
+
+// manages returns true if the record is one of the email records replaced by the configuration.
+func (e *EmailDNS) manages(record ZoneRecord) bool {
+	if record.SystemRecord {
+		return false
+	}
+
+	name := strings.ToLower(record.Name)
+	switch {
+	case record.Type == "MX":
+		return name == "" && len(e.MX) > 0
+	case record.Type != "TXT":
+		return false
+	case name == "":
+		return e.SPF != nil && isSPFContent(record.Content)
+	case name == "_dmarc":
+		return e.DMARC != nil && isDMARCContent(record.Content)
+	}
+	for _, key := range e.DKIM {
+		if name == key.RecordName() {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailDNSChanges represents the changes made by ApplyEmailDNS.
+type EmailDNSChanges struct {
+	Created   []ZoneRecord
+	Deleted   []ZoneRecord
+	Unchanged []ZoneRecord
+}
+
+// ApplyEmailDNS makes the email records of the zone match the configuration.
+//
+// The missing records are created first, then the email records that are not part
+// of the configuration are deleted, such as a previous SPF policy or a previous
+// mail server, so that the zone never lacks a record while the changes are applied.
+// The deletions are skipped when a creation fails. The changes made so far are
+// returned along with the error.
+func ApplyEmailDNS(ctx context.Context, c *Client, accountID string, zoneName string, config *EmailDNS, options *BulkOptions) (*EmailDNSChanges, error) {
+	current, err := listAllZoneRecords(c, accountID, zoneName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing records for %v: %v", zoneName, err)
+	}
+
+	changes := &EmailDNSChanges{}
+	desired := config.Records()
+	found := make([]bool, len(desired))
+	var deletes []ZoneRecord
+	for _, record := range current {
+		if !config.manages(record) {
+			continue
+		}
+
+		matched := false
+		for i, want := range desired {
+			if !found[i] && emailRecordMatches(record, want) {
+				found[i], matched = true, true
+				break
+			}
+		}
+		if matched {
+			changes.Unchanged = append(changes.Unchanged, record)
+		} else {
+			deletes = append(deletes, record)
+		}
+	}
+
+	var creates []ZoneRecord
+	for i, record := range desired {
+		if !found[i] {
+			creates = append(creates, record)
+		}
+	}
+
+	if len(creates) > 0 {
+		results, err := c.Zones.CreateRecords(ctx, accountID, zoneName, creates, options)
+		for _, result := range results {
+			if result.Record != nil {
+				changes.Created = append(changes.Created, *result.Record)
+			}
+		}
+		if err != nil {
+			return changes, fmt.Errorf("creating email records for %v: %v", zoneName, err)
+		}
+	}
+
+	if len(deletes) > 0 {
+		ids := make([]int64, len(deletes))
+		for i, record := range deletes {
+			ids[i] = record.ID
+		}
+		results, err := c.Zones.DeleteRecords(ctx, accountID, zoneName, ids, options)
+		for i, result := range results {
+			if result.Err == nil {
+				changes.Deleted = append(changes.Deleted, deletes[i])
+			}
+		}
+		if err != nil {
+			return changes, fmt.Errorf("deleting email records for %v: %v", zoneName, err)
+		}
+	}
+
+	return changes, nil
+}
+
+// emailRecordMatches returns true if the record of the zone has the content of the desired record.
+// The TTL is ignored.
+func emailRecordMatches(record ZoneRecord, want ZoneRecord) bool {
+	if record.Type != want.Type || !strings.EqualFold(record.Name, want.Name) {
+		return false
+	}
+	if record.Type == "MX" {
+		return record.Priority == want.Priority && normalizeHostName(record.Content) == want.Content
+	}
+	return unquoteImportText(strings.TrimSpace(record.Content)) == unquoteImportText(want.Content)
+}
+
+// EmailDNSIssue represents a problem with the email records of a zone.
+type EmailDNSIssue struct {
+	// The name and the type of the record, relative to the zone.
+	Name string
+	Type string
+
+	Message string
+}
+
+// String returns a human-readable description of the issue.
+func (i EmailDNSIssue) String() string {
+	name := i.Name
+	if name == "" {
+		name = "@"
+	}
+	return fmt.Sprintf("%v %v: %v", name, i.Type, i.Message)
+}
+
+// AuditEmailDNS checks the records of a zone for common mistakes in the email records:
+// multiple or invalid SPF and DMARC policies, SPF policies above the limit of
+// 10 DNS lookups, TXT character strings longer than 255 bytes, DKIM records
+// without a public key and mail servers that are IP addresses or CNAME records.
+//
+// The SPF lookups are counted for the record itself: use SPFRecord.Flatten
+// to resolve the included records.
+func AuditEmailDNS(zoneName string, records []ZoneRecord) []EmailDNSIssue {
+	var issues []EmailDNSIssue
+	report := func(record ZoneRecord, format string, args ...interface{}) {
+		issues = append(issues, EmailDNSIssue{Name: strings.ToLower(record.Name), Type: record.Type, Message: fmt.Sprintf(format, args...)})
+	}
+
+	aliases := map[string]bool{}
+	for _, record := range records {
+		if record.Type == "CNAME" {
+			aliases[normalizeHostName(fqdn(record.Name, zoneName))] = true
+		}
+	}
+
+	spfCount := map[string]int{}
+	dmarcCount := map[string]int{}
+	for _, record := range records {
+		name := strings.ToLower(record.Name)
+
+		switch record.Type {
+		case "MX":
+			host := normalizeHostName(record.Content)
+			switch {
+			case net.ParseIP(host) != nil:
+				report(record, "points to the IP address %v instead of a host name", host)
+			case aliases[host]:
+				report(record, "points to %v, which is a CNAME record", host)
+			}
+		case "SPF":
+			report(record, "uses the deprecated SPF record type, publish the policy in a TXT record")
+		case "TXT":
+			for _, length := range txtStringLengths(record.Content) {
+				if length > txtMaxStringLength {
+					report(record, "has a character string of %v bytes, longer than %v", length, txtMaxStringLength)
+				}
+			}
+
+			switch {
+			case isSPFContent(record.Content):
+				spfCount[name]++
+				for _, message := range auditSPF(record.Content) {
+					report(record, "%v", message)
+				}
+			case isDMARCContent(record.Content):
+				if name != "_dmarc" && !strings.HasPrefix(name, "_dmarc.") {
+					report(record, "has a DMARC policy outside of a _dmarc record")
+					break
+				}
+				dmarcCount[name]++
+				if _, err := ParseDMARC(record.Content); err != nil {
+					report(record, "has an invalid DMARC policy: %v", err)
+				}
+			case name == "_domainkey" || strings.HasSuffix(name, "._domainkey"):
+				if _, ok := dkimPublicKey(record.Content); !ok {
+					report(record, "has a DKIM record without a public key")
+				}
+			}
+		}
+	}
+
+	for _, name := range sortedCountKeys(spfCount) {
+		if spfCount[name] > 1 {
+			issues = append(issues, EmailDNSIssue{Name: name, Type: "TXT", Message: fmt.Sprintf("has %v SPF policies, which fails the SPF checks", spfCount[name])})
+		}
+	}
+	for _, name := range sortedCountKeys(dmarcCount) {
+		if dmarcCount[name] > 1 {
+			issues = append(issues, EmailDNSIssue{Name: name, Type: "TXT", Message: fmt.Sprintf("has %v DMARC policies, which disables DMARC", dmarcCount[name])})
+		}
+	}
+
+	return issues
+}
+
+func auditSPF(content string) []string {
+	record, err := ParseSPF(content)
+	if err != nil {
+		return []string{fmt.Sprintf("has an invalid SPF policy: %v", err)}
+	}
+
+	var messages []string
+	if lookups := record.DNSLookups(); lookups > spfMaxLookups {
+		messages = append(messages, fmt.Sprintf("has an SPF policy requiring %v DNS lookups, more than %v", lookups, spfMaxLookups))
+	}
+
+	var all *SPFMechanism
+	for i, mechanism := range record.Mechanisms {
+		if mechanism.Type == "all" {
+			all = &record.Mechanisms[i]
+			break
+		}
+	}
+	switch {
+	case all == nil && record.Redirect == "":
+		messages = append(messages, "has an SPF policy without an all mechanism")
+	case all != nil && all.Qualifier == SPFPass:
+		messages = append(messages, "has an SPF policy authorizing every sender with +all")
+	}
+	return messages
+}
+
+func sortedCountKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// AuditZoneEmailDNS checks the email records of a zone, see AuditEmailDNS.
+func AuditZoneEmailDNS(c *Client, accountID string, zoneName string) ([]EmailDNSIssue, error) {
+	records, err := listAllZoneRecords(c, accountID, zoneName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing records for %v: %v", zoneName, err)
+	}
+	return AuditEmailDNS(zoneName, records), nil
+}
@@ -0,0 +1,141 @@
+package dnsimple
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// txtMaxStringLength is the maximum length of a character string of a TXT record (RFC 1035, section 3.3).
+const txtMaxStringLength = 255
+
+// DKIMKeyType identifies the algorithm of a DKIM key.
+type DKIMKeyType string
+
+const (
+	// DKIMKeyRSA2048 is a 2048-bit RSA key.
+	DKIMKeyRSA2048 = DKIMKeyType("rsa2048")
+)
+
+// DKIMKey represents a DKIM signing key, along with the content of the TXT record
+// that publishes its public key.
+type DKIMKey struct {
+	// The selector of the key: the record is named <selector>._domainkey.
+	Selector string
+
+	// The PEM-encoded private key, to configure in the mail server.
+	// It's empty when the key is built from an existing record.
+	PrivateKey string
+
+	// The content of the DKIM record, e.g. v=DKIM1; k=rsa; p=MIIBIjANBgkqhkiG...
+	Record string
+}
+
+// GenerateDKIMKey generates a DKIM key pair for the selector.
+// The private key is PEM-encoded in the PKCS#1 format.
+func GenerateDKIMKey(selector string, keyType DKIMKeyType) (*DKIMKey, error) {
+	if selector == "" {
+		return nil, fmt.Errorf("DKIM selector is required")
+	}
+	if keyType != DKIMKeyRSA2048 {
+		return nil, fmt.Errorf("unsupported DKIM key type %q", keyType)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := encodePrivateKeyPEM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DKIMKey{
+		Selector:   strings.ToLower(selector),
+		PrivateKey: privateKey,
+		Record:     fmt.Sprintf("v=DKIM1; k=rsa; p=%v", base64.StdEncoding.EncodeToString(publicKey)),
+	}, nil
+}
+
+// RecordName returns the name of the DKIM record, relative to the zone.
+func (k *DKIMKey) RecordName() string {
+	return k.Selector + "._domainkey"
+}
+
+// ZoneRecord returns the TXT record that publishes the public key.
+// The content is split into character strings of 255 bytes at most.
+func (k *DKIMKey) ZoneRecord(ttl int) ZoneRecord {
+	return ZoneRecord{Type: "TXT", Name: k.RecordName(), Content: splitTXTContent(k.Record), TTL: ttl}
+}
+
+// dkimPublicKey returns the p= tag of the content of a DKIM record, and whether it's present.
+func dkimPublicKey(content string) (string, bool) {
+	for _, tag := range strings.Split(unquoteImportText(strings.TrimSpace(content)), ";") {
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(tag, "p=") {
+			return strings.TrimSpace(tag[2:]), true
+		}
+	}
+	return "", false
+}
+
+// splitTXTContent returns the content of a TXT record as quoted character strings
+// of 255 bytes at most, when it's too long for a single character string.
+func splitTXTContent(text string) string {
+	if len(text) <= txtMaxStringLength {
+		return text
+	}
+
+	var parts []string
+	for len(text) > 0 {
+		n := txtMaxStringLength
+		if n > len(text) {
+			n = len(text)
+		}
+		parts = append(parts, `"`+text[:n]+`"`)
+		text = text[n:]
+	}
+	return strings.Join(parts, " ")
+}
+
+// txtStringLengths returns the length of each character string of the content
+// of a TXT record. Unquoted content is a single character string.
+func txtStringLengths(content string) []int {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, `"`) {
+		return []int{len(content)}
+	}
+
+	var lengths []int
+	length, inQuote := 0, false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '"' && inQuote:
+			lengths = append(lengths, length)
+			length, inQuote = 0, false
+		case c == '"':
+			inQuote = true
+		case !inQuote:
+		case c == '\\' && i+3 < len(content) && isDigits(content[i+1:i+4]):
+			length++
+			i += 3
+		case c == '\\' && i+1 < len(content):
+			length++
+			i++
+		default:
+			length++
+		}
+	}
+	if inQuote {
+		lengths = append(lengths, length)
+	}
+	return lengths
+}
@@ -0,0 +1,91 @@
+package dnsimple
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateDKIMKey(t *testing.T) {
+	key, err := GenerateDKIMKey("Mail2024", DKIMKeyRSA2048)
+	if err != nil {
+		t.Fatalf("GenerateDKIMKey() returned error: %v", err)
+	}
+
+	block, _ := pem.Decode([]byte(key.PrivateKey))
+	if block == nil || block.Type != "RSA PRIVATE KEY" {
+		t.Fatalf("GenerateDKIMKey() returned an invalid private key")
+	}
+	private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatalf("GenerateDKIMKey() returned an invalid private key: %v", err)
+	}
+
+	prefix := "v=DKIM1; k=rsa; p="
+	if !strings.HasPrefix(key.Record, prefix) {
+		t.Fatalf("GenerateDKIMKey() returned record `%v`, expected to start with `%v`", key.Record, prefix)
+	}
+	public, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(key.Record, prefix))
+	if err != nil {
+		t.Fatalf("GenerateDKIMKey() returned an invalid public key: %v", err)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(public)
+	if err != nil || !reflect.DeepEqual(&private.PublicKey, parsed) {
+		t.Errorf("GenerateDKIMKey() returned a public key not matching the private key")
+	}
+
+	if want, got := "mail2024._domainkey", key.RecordName(); want != got {
+		t.Errorf("DKIMKey.RecordName() expected to be `%v`, got `%v`", want, got)
+	}
+
+	if _, err := GenerateDKIMKey("mail", DKIMKeyType("dsa")); err == nil {
+		t.Errorf("GenerateDKIMKey() expected to return an error for an unsupported key type")
+	}
+	if _, err := GenerateDKIMKey("", DKIMKeyRSA2048); err == nil {
+		t.Errorf("GenerateDKIMKey() expected to return an error without a selector")
+	}
+}
+
+func TestDKIMKey_ZoneRecord(t *testing.T) {
+	key, err := GenerateDKIMKey("mail", DKIMKeyRSA2048)
+	if err != nil {
+		t.Fatalf("GenerateDKIMKey() returned error: %v", err)
+	}
+
+	record := key.ZoneRecord(3600)
+	if want, got := "mail._domainkey", record.Name; want != got {
+		t.Errorf("DKIMKey.ZoneRecord() returned name `%v`, expected `%v`", got, want)
+	}
+	if want, got := 3600, record.TTL; want != got {
+		t.Errorf("DKIMKey.ZoneRecord() returned TTL `%v`, expected `%v`", got, want)
+	}
+
+	// The RSA public key doesn't fit a single character string.
+	lengths := txtStringLengths(record.Content)
+	if want, got := 2, len(lengths); want != got {
+		t.Fatalf("DKIMKey.ZoneRecord() returned %v character strings, expected %v", got, want)
+	}
+	if want, got := 255, lengths[0]; want != got {
+		t.Errorf("DKIMKey.ZoneRecord() returned a first character string of %v bytes, expected %v", got, want)
+	}
+	if want, got := key.Record, unquoteImportText(record.Content); want != got {
+		t.Errorf("DKIMKey.ZoneRecord() returned content `%v`, expected `%v`", got, want)
+	}
+}
+
+func TestTxtStringLengths(t *testing.T) {
+	tests := map[string][]int{
+		`v=spf1 -all`:            {11},
+		`"abc" "de\"f" "\065bc"`: {3, 4, 3},
+		`"unterminated`:          {12},
+		`"" "a"`:                 {0, 1},
+	}
+	for content, want := range tests {
+		if got := txtStringLengths(content); !reflect.DeepEqual(want, got) {
+			t.Errorf("txtStringLengths(%q) expected to be `%v`, got `%v`", content, want, got)
+		}
+	}
+}
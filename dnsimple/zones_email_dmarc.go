@@ -0,0 +1,167 @@
+package dnsimple
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DMARCPolicy represents the action requested for the emails that fail the DMARC checks.
+type DMARCPolicy string
+
+const (
+	// DMARCNone is the policy that only monitors the emails that fail the checks.
+	DMARCNone = DMARCPolicy("none")
+
+	// DMARCQuarantine is the policy that marks the emails that fail the checks as suspicious.
+	DMARCQuarantine = DMARCPolicy("quarantine")
+
+	// DMARCReject is the policy that rejects the emails that fail the checks.
+	DMARCReject = DMARCPolicy("reject")
+)
+
+// DMARCRecord represents a DMARC policy (RFC 7489), published in the TXT record
+// named _dmarc of the domain.
+type DMARCRecord struct {
+	Policy DMARCPolicy
+
+	// The policy of the subdomains, if different from Policy.
+	SubdomainPolicy DMARCPolicy
+
+	// The percentage of the emails the policy applies to. Zero means 100.
+	Percent int
+
+	// The mailto: URIs the aggregate and failure reports are sent to.
+	AggregateReports []string
+	ForensicReports  []string
+
+	// The alignment modes of DKIM and SPF: "r" (relaxed, the default) or "s" (strict).
+	DKIMAlignment string
+	SPFAlignment  string
+
+	// The failure reporting options (e.g. "1" or "d:s"), if any.
+	FailureOptions string
+
+	// The interval between the aggregate reports, in seconds. Zero means 86400.
+	ReportInterval int
+}
+
+// ParseDMARC parses the content of a DMARC TXT record.
+func ParseDMARC(content string) (*DMARCRecord, error) {
+	tags := strings.Split(unquoteImportText(strings.TrimSpace(content)), ";")
+	if strings.Replace(strings.TrimSpace(tags[0]), " ", "", -1) != "v=DMARC1" {
+		return nil, fmt.Errorf("DMARC record must start with v=DMARC1")
+	}
+
+	record := &DMARCRecord{}
+	for _, tag := range tags[1:] {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		i := strings.Index(tag, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid DMARC tag %v", tag)
+		}
+		name, value := strings.ToLower(strings.TrimSpace(tag[:i])), strings.TrimSpace(tag[i+1:])
+
+		var err error
+		switch name {
+		case "p":
+			record.Policy, err = parseDMARCPolicy(value)
+		case "sp":
+			record.SubdomainPolicy, err = parseDMARCPolicy(value)
+		case "pct":
+			record.Percent, err = parseDMARCInt(name, value, 0, 100)
+		case "ri":
+			record.ReportInterval, err = parseDMARCInt(name, value, 0, 1<<31-1)
+		case "rua":
+			record.AggregateReports = splitDMARCList(value)
+		case "ruf":
+			record.ForensicReports = splitDMARCList(value)
+		case "adkim", "aspf":
+			value = strings.ToLower(value)
+			if value != "r" && value != "s" {
+				err = fmt.Errorf("invalid DMARC alignment %v=%v", name, value)
+			} else if name == "adkim" {
+				record.DKIMAlignment = value
+			} else {
+				record.SPFAlignment = value
+			}
+		case "fo":
+			record.FailureOptions = value
+		}
+		// Unknown tags are ignored (RFC 7489, section 6.3).
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if record.Policy == "" {
+		return nil, fmt.Errorf("DMARC record has no policy")
+	}
+	return record, nil
+}
+
+func parseDMARCPolicy(value string) (DMARCPolicy, error) {
+	switch policy := DMARCPolicy(strings.ToLower(value)); policy {
+	case DMARCNone, DMARCQuarantine, DMARCReject:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid DMARC policy %v", value)
+	}
+}
+
+func parseDMARCInt(name string, value string, min int, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("invalid DMARC value %v=%v", name, value)
+	}
+	return n, nil
+}
+
+func splitDMARCList(value string) []string {
+	var uris []string
+	for _, uri := range strings.Split(value, ",") {
+		if uri = strings.TrimSpace(uri); uri != "" {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+// String returns the content of the DMARC TXT record. The tags set to their
+// default values are omitted.
+func (r *DMARCRecord) String() string {
+	tags := []string{"v=DMARC1", "p=" + string(r.Policy)}
+	if r.SubdomainPolicy != "" {
+		tags = append(tags, "sp="+string(r.SubdomainPolicy))
+	}
+	if r.Percent != 0 && r.Percent != 100 {
+		tags = append(tags, "pct="+strconv.Itoa(r.Percent))
+	}
+	if len(r.AggregateReports) > 0 {
+		tags = append(tags, "rua="+strings.Join(r.AggregateReports, ","))
+	}
+	if len(r.ForensicReports) > 0 {
+		tags = append(tags, "ruf="+strings.Join(r.ForensicReports, ","))
+	}
+	if r.DKIMAlignment != "" && r.DKIMAlignment != "r" {
+		tags = append(tags, "adkim="+r.DKIMAlignment)
+	}
+	if r.SPFAlignment != "" && r.SPFAlignment != "r" {
+		tags = append(tags, "aspf="+r.SPFAlignment)
+	}
+	if r.FailureOptions != "" {
+		tags = append(tags, "fo="+r.FailureOptions)
+	}
+	if r.ReportInterval != 0 && r.ReportInterval != 86400 {
+		tags = append(tags, "ri="+strconv.Itoa(r.ReportInterval))
+	}
+	return strings.Join(tags, "; ")
+}
+
+// isDMARCContent returns true if the content of a TXT record is a DMARC record.
+func isDMARCContent(content string) bool {
+	return strings.HasPrefix(strings.Replace(unquoteImportText(strings.TrimSpace(content)), " ", "", -1), "v=DMARC1")
+}
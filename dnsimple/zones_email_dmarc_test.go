@@ -0,0 +1,54 @@
+package dnsimple
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDMARC(t *testing.T) {
+	record, err := ParseDMARC("v=DMARC1; p=Quarantine; sp=reject; pct=50; rua=mailto:dmarc@example.com, mailto:reports@example.net; adkim=s; aspf=r; fo=1; ri=3600; foo=bar;")
+	if err != nil {
+		t.Fatalf("ParseDMARC() returned error: %v", err)
+	}
+
+	want := &DMARCRecord{
+		Policy:           DMARCQuarantine,
+		SubdomainPolicy:  DMARCReject,
+		Percent:          50,
+		AggregateReports: []string{"mailto:dmarc@example.com", "mailto:reports@example.net"},
+		DKIMAlignment:    "s",
+		SPFAlignment:     "r",
+		FailureOptions:   "1",
+		ReportInterval:   3600,
+	}
+	if !reflect.DeepEqual(want, record) {
+		t.Errorf("ParseDMARC() returned `%+v`, expected `%+v`", record, want)
+	}
+
+	wantString := "v=DMARC1; p=quarantine; sp=reject; pct=50; rua=mailto:dmarc@example.com,mailto:reports@example.net; adkim=s; fo=1; ri=3600"
+	if got := record.String(); wantString != got {
+		t.Errorf("DMARCRecord.String() expected to be `%v`, got `%v`", wantString, got)
+	}
+}
+
+func TestDMARCRecord_String(t *testing.T) {
+	record := &DMARCRecord{Policy: DMARCReject, Percent: 100, ReportInterval: 86400}
+	if want, got := "v=DMARC1; p=reject", record.String(); want != got {
+		t.Errorf("DMARCRecord.String() expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestParseDMARC_Invalid(t *testing.T) {
+	for _, content := range []string{
+		"v=spf1 -all",
+		"v=DMARC1",
+		"v=DMARC1; p=block",
+		"v=DMARC1; p=none; pct=150",
+		"v=DMARC1; p=none; adkim=x",
+		"v=DMARC1; p=none; rua",
+	} {
+		if _, err := ParseDMARC(content); err == nil {
+			t.Errorf("ParseDMARC(%q) expected to return an error", content)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package dnsimple
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// spfMaxLookups is the maximum number of DNS lookups of an SPF evaluation (RFC 7208, section 4.6.4).
+const spfMaxLookups = 10
+
+// SPFQualifier represents the result of an SPF mechanism that matches.
+type SPFQualifier string
+
+const (
+	// SPFPass is the qualifier of a mechanism that authorizes the sender.
+	SPFPass = SPFQualifier("+")
+
+	// SPFFail is the qualifier of a mechanism that rejects the sender.
+	SPFFail = SPFQualifier("-")
+
+	// SPFSoftFail is the qualifier of a mechanism that marks the sender as probably not authorized.
+	SPFSoftFail = SPFQualifier("~")
+
+	// SPFNeutral is the qualifier of a mechanism that makes no assertion about the sender.
+	SPFNeutral = SPFQualifier("?")
+)
+
+// SPFMechanism represents a mechanism of an SPF record, such as include:_spf.example.com or -all.
+type SPFMechanism struct {
+	// The qualifier of the mechanism. An empty qualifier means SPFPass.
+	Qualifier SPFQualifier
+
+	// The mechanism: all, include, a, mx, ptr, ip4, ip6 or exists.
+	Type string
+
+	// The argument of the mechanism, if any: a domain, an IP address or a network,
+	// optionally followed by the prefix lengths of the a and mx mechanisms (e.g. example.com/24).
+	Value string
+}
+
+// String returns the mechanism as written in an SPF record.
+func (m SPFMechanism) String() string {
+	s := m.Type
+	if m.Qualifier != "" && m.Qualifier != SPFPass {
+		s = string(m.Qualifier) + s
+	}
+	if m.Value == "" {
+		return s
+	}
+	if strings.HasPrefix(m.Value, "/") {
+		return s + m.Value
+	}
+	return s + ":" + m.Value
+}
+
+// SPFRecord represents an SPF policy (RFC 7208).
+type SPFRecord struct {
+	Mechanisms []SPFMechanism
+
+	// The domain whose policy applies when no mechanism matches, if any.
+	Redirect string
+
+	// The domain of the explanation of the failures, if any.
+	Explanation string
+}
+
+// ParseSPF parses the content of an SPF TXT record.
+func ParseSPF(content string) (*SPFRecord, error) {
+	fields := strings.Fields(unquoteImportText(strings.TrimSpace(content)))
+	if len(fields) == 0 || !strings.EqualFold(fields[0], "v=spf1") {
+		return nil, fmt.Errorf("SPF record must start with v=spf1")
+	}
+
+	record := &SPFRecord{}
+	for _, term := range fields[1:] {
+		if i := strings.Index(term, "="); i > 0 && !strings.ContainsAny(term[:i], ":/") {
+			name, value := strings.ToLower(term[:i]), term[i+1:]
+			switch {
+			case value == "":
+				return nil, fmt.Errorf("SPF modifier %v has no value", name)
+			case name == "redirect" && record.Redirect == "":
+				record.Redirect = value
+			case name == "exp" && record.Explanation == "":
+				record.Explanation = value
+			case name == "redirect" || name == "exp":
+				return nil, fmt.Errorf("SPF modifier %v is repeated", name)
+			}
+			// Unknown modifiers are ignored (RFC 7208, section 6).
+			continue
+		}
+
+		mechanism, err := parseSPFMechanism(term)
+		if err != nil {
+			return nil, err
+		}
+		record.Mechanisms = append(record.Mechanisms, mechanism)
+	}
+
+	return record, nil
+}
+
+func parseSPFMechanism(term string) (SPFMechanism, error) {
+	mechanism := SPFMechanism{Qualifier: SPFPass}
+	switch SPFQualifier(term[:1]) {
+	case SPFPass, SPFFail, SPFSoftFail, SPFNeutral:
+		mechanism.Qualifier, term = SPFQualifier(term[:1]), term[1:]
+	}
+
+	name, value := term, ""
+	if i := strings.IndexAny(term, ":/"); i >= 0 {
+		name, value = term[:i], strings.TrimPrefix(term[i:], ":")
+	}
+	mechanism.Type, mechanism.Value = strings.ToLower(name), value
+
+	switch mechanism.Type {
+	case "all":
+		if value != "" {
+			return mechanism, fmt.Errorf("SPF mechanism %v takes no argument", term)
+		}
+	case "include", "exists":
+		if value == "" {
+			return mechanism, fmt.Errorf("SPF mechanism %v requires a domain", term)
+		}
+	case "ip4", "ip6":
+		ip := value
+		if _, network, err := net.ParseCIDR(value); err == nil {
+			ip = network.IP.String()
+		}
+		parsed := net.ParseIP(ip)
+		if parsed == nil || (parsed.To4() != nil) != (mechanism.Type == "ip4") {
+			return mechanism, fmt.Errorf("SPF mechanism %v has an invalid address", term)
+		}
+	case "a", "mx", "ptr":
+	default:
+		return mechanism, fmt.Errorf("unknown SPF mechanism %v", term)
+	}
+
+	return mechanism, nil
+}
+
+// String returns the content of the SPF TXT record.
+func (r *SPFRecord) String() string {
+	terms := []string{"v=spf1"}
+	for _, mechanism := range r.Mechanisms {
+		terms = append(terms, mechanism.String())
+	}
+	if r.Redirect != "" {
+		terms = append(terms, "redirect="+r.Redirect)
+	}
+	if r.Explanation != "" {
+		terms = append(terms, "exp="+r.Explanation)
+	}
+	return strings.Join(terms, " ")
+}
+
+// DNSLookups returns the number of DNS lookups the mechanisms and the modifiers
+// of the record cause, not including the lookups of the included records.
+// An SPF evaluation fails when it requires more than 10 lookups.
+func (r *SPFRecord) DNSLookups() int {
+	lookups := 0
+	for _, mechanism := range r.Mechanisms {
+		switch mechanism.Type {
+		case "include", "a", "mx", "ptr", "exists":
+			lookups++
+		}
+	}
+	if r.Redirect != "" {
+		lookups++
+	}
+	return lookups
+}
+
+// SPFResolver resolves the names referenced by SPF records.
+// *net.Resolver implements it, starting with Go 1.8.
+type SPFResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+// Flatten returns a copy of the record where the include mechanisms are replaced
+// by the ip4 and ip6 mechanisms of the addresses they authorize, so that the record
+// stays below the limit of 10 DNS lookups.
+//
+// The included records are resolved recursively, including their a and mx mechanisms
+// and their redirect modifiers. Flatten fails when an included record uses a mechanism
+// that can't be flattened, such as exists or ptr.
+func (r *SPFRecord) Flatten(ctx context.Context, resolver SPFResolver) (*SPFRecord, error) {
+	flattener := &spfFlattener{resolver: resolver, seen: map[string]bool{}}
+
+	flattened := &SPFRecord{Redirect: r.Redirect, Explanation: r.Explanation}
+	for _, mechanism := range r.Mechanisms {
+		if mechanism.Type != "include" {
+			flattened.Mechanisms = append(flattened.Mechanisms, mechanism)
+			continue
+		}
+
+		networks, err := flattener.include(ctx, mechanism.Value)
+		if err != nil {
+			return nil, fmt.Errorf("flattening %v: %v", mechanism, err)
+		}
+		for _, network := range networks {
+			flattened.Mechanisms = appendSPFNetwork(flattened.Mechanisms, mechanism.Qualifier, network)
+		}
+	}
+
+	return flattened, nil
+}
+
+type spfFlattener struct {
+	resolver SPFResolver
+	lookups  int
+	seen     map[string]bool
+}
+
+func (f *spfFlattener) lookup() error {
+	f.lookups++
+	if f.lookups > spfMaxLookups*spfMaxLookups {
+		return fmt.Errorf("too many DNS lookups")
+	}
+	return nil
+}
+
+// include returns the networks authorized by the SPF record of the domain.
+// Only the mechanisms that pass authorize networks, as the included record
+// only matches when it passes.
+func (f *spfFlattener) include(ctx context.Context, domainName string) ([]*net.IPNet, error) {
+	domainName = strings.ToLower(strings.TrimSuffix(domainName, "."))
+	if f.seen[domainName] {
+		return nil, fmt.Errorf("SPF record of %v includes itself", domainName)
+	}
+	f.seen[domainName] = true
+	defer delete(f.seen, domainName)
+
+	if err := f.lookup(); err != nil {
+		return nil, err
+	}
+	record, err := f.record(ctx, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	var networks []*net.IPNet
+	hasAll := false
+	for _, mechanism := range record.Mechanisms {
+		if mechanism.Type == "all" {
+			hasAll = true
+		}
+		if mechanism.Qualifier != SPFPass {
+			continue
+		}
+
+		var found []*net.IPNet
+		switch mechanism.Type {
+		case "all":
+			return nil, fmt.Errorf("SPF record of %v authorizes all the addresses", domainName)
+		case "ip4", "ip6":
+			found = []*net.IPNet{parseSPFNetwork(mechanism.Value)}
+		case "include":
+			found, err = f.include(ctx, mechanism.Value)
+		case "a", "mx":
+			found, err = f.hosts(ctx, domainName, mechanism)
+		default:
+			return nil, fmt.Errorf("SPF mechanism %v of %v can't be flattened", mechanism, domainName)
+		}
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, found...)
+	}
+
+	if record.Redirect != "" && !hasAll {
+		found, err := f.include(ctx, record.Redirect)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, found...)
+	}
+
+	return networks, nil
+}
+
+// record returns the SPF record of the domain.
+func (f *spfFlattener) record(ctx context.Context, domainName string) (*SPFRecord, error) {
+	txts, err := f.resolver.LookupTXT(ctx, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *SPFRecord
+	for _, txt := range txts {
+		if !isSPFContent(txt) {
+			continue
+		}
+		if record != nil {
+			return nil, fmt.Errorf("%v has multiple SPF records", domainName)
+		}
+		if record, err = ParseSPF(txt); err != nil {
+			return nil, fmt.Errorf("SPF record of %v: %v", domainName, err)
+		}
+	}
+	if record == nil {
+		return nil, fmt.Errorf("%v has no SPF record", domainName)
+	}
+	return record, nil
+}
+
+// hosts returns the networks of the addresses of the a or mx mechanism.
+func (f *spfFlattener) hosts(ctx context.Context, domainName string, mechanism SPFMechanism) ([]*net.IPNet, error) {
+	target, prefix4, prefix6, err := splitSPFDualCIDR(mechanism.Value)
+	if err != nil {
+		return nil, err
+	}
+	if target == "" {
+		target = domainName
+	}
+
+	hosts := []string{target}
+	if mechanism.Type == "mx" {
+		if err := f.lookup(); err != nil {
+			return nil, err
+		}
+		mxs, err := f.resolver.LookupMX(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		hosts = hosts[:0]
+		for _, mx := range mxs {
+			hosts = append(hosts, mx.Host)
+		}
+	}
+
+	var networks []*net.IPNet
+	for _, host := range hosts {
+		if err := f.lookup(); err != nil {
+			return nil, err
+		}
+		addresses, err := f.resolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, address := range addresses {
+			if ip4 := address.IP.To4(); ip4 != nil {
+				networks = append(networks, &net.IPNet{IP: ip4.Mask(net.CIDRMask(prefix4, 32)), Mask: net.CIDRMask(prefix4, 32)})
+			} else {
+				networks = append(networks, &net.IPNet{IP: address.IP.Mask(net.CIDRMask(prefix6, 128)), Mask: net.CIDRMask(prefix6, 128)})
+			}
+		}
+	}
+	return networks, nil
+}
+
+// splitSPFDualCIDR splits the argument of an a or mx mechanism into the domain,
+// and the IPv4 and IPv6 prefix lengths (e.g. example.com/24//64).
+func splitSPFDualCIDR(value string) (string, int, int, error) {
+	prefix4, prefix6 := 32, 128
+
+	if i := strings.Index(value, "//"); i >= 0 {
+		length, err := strconv.Atoi(value[i+2:])
+		if err != nil || length < 0 || length > 128 {
+			return "", 0, 0, fmt.Errorf("invalid IPv6 prefix length in %v", value)
+		}
+		value, prefix6 = value[:i], length
+	}
+	if i := strings.Index(value, "/"); i >= 0 {
+		length, err := strconv.Atoi(value[i+1:])
+		if err != nil || length < 0 || length > 32 {
+			return "", 0, 0, fmt.Errorf("invalid IPv4 prefix length in %v", value)
+		}
+		value, prefix4 = value[:i], length
+	}
+
+	return value, prefix4, prefix6, nil
+}
+
+// parseSPFNetwork returns the network of the argument of an ip4 or ip6 mechanism.
+func parseSPFNetwork(value string) *net.IPNet {
+	if _, network, err := net.ParseCIDR(value); err == nil {
+		return network
+	}
+	ip := net.ParseIP(value)
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// appendSPFNetwork appends the ip4 or ip6 mechanism of the network, unless it's already present.
+func appendSPFNetwork(mechanisms []SPFMechanism, qualifier SPFQualifier, network *net.IPNet) []SPFMechanism {
+	mechanism := SPFMechanism{Qualifier: qualifier, Type: "ip6", Value: network.IP.String()}
+	if network.IP.To4() != nil {
+		mechanism.Type = "ip4"
+	}
+	if ones, bits := network.Mask.Size(); ones != bits {
+		mechanism.Value = network.String()
+	}
+
+	for _, m := range mechanisms {
+		if m.Type == mechanism.Type && m.Value == mechanism.Value {
+			return mechanisms
+		}
+	}
+	return append(mechanisms, mechanism)
+}
+
+// isSPFContent returns true if the content of a TXT record is an SPF record.
+func isSPFContent(content string) bool {
+	text := strings.ToLower(unquoteImportText(strings.TrimSpace(content)))
+	return text == "v=spf1" || strings.HasPrefix(text, "v=spf1 ")
+}
@@ -0,0 +1,148 @@
+package dnsimple
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeSPFResolver resolves the names of the SPF tests.
+type fakeSPFResolver struct {
+	txt map[string][]string
+	ips map[string][]string
+	mx  map[string][]string
+}
+
+func (r *fakeSPFResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	txt, ok := r.txt[name]
+	if !ok {
+		return nil, fmt.Errorf("no such host %v", name)
+	}
+	return txt, nil
+}
+
+func (r *fakeSPFResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	var addresses []net.IPAddr
+	for _, ip := range r.ips[host] {
+		addresses = append(addresses, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addresses, nil
+}
+
+func (r *fakeSPFResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	var mxs []*net.MX
+	for _, host := range r.mx[name] {
+		mxs = append(mxs, &net.MX{Host: host})
+	}
+	return mxs, nil
+}
+
+func TestParseSPF(t *testing.T) {
+	record, err := ParseSPF(`"v=spf1 ip4:192.0.2.0/24 a/28 mx:example.org include:_spf.google.com " "~all redirect=_spf.example.com unknown=1"`)
+	if err != nil {
+		t.Fatalf("ParseSPF() returned error: %v", err)
+	}
+
+	want := &SPFRecord{
+		Mechanisms: []SPFMechanism{
+			{Qualifier: SPFPass, Type: "ip4", Value: "192.0.2.0/24"},
+			{Qualifier: SPFPass, Type: "a", Value: "/28"},
+			{Qualifier: SPFPass, Type: "mx", Value: "example.org"},
+			{Qualifier: SPFPass, Type: "include", Value: "_spf.google.com"},
+			{Qualifier: SPFSoftFail, Type: "all"},
+		},
+		Redirect: "_spf.example.com",
+	}
+	if !reflect.DeepEqual(want, record) {
+		t.Errorf("ParseSPF() returned `%+v`, expected `%+v`", record, want)
+	}
+
+	if want, got := "v=spf1 ip4:192.0.2.0/24 a/28 mx:example.org include:_spf.google.com ~all redirect=_spf.example.com", record.String(); want != got {
+		t.Errorf("SPFRecord.String() expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := 4, record.DNSLookups(); want != got {
+		t.Errorf("SPFRecord.DNSLookups() expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestParseSPF_Invalid(t *testing.T) {
+	for _, content := range []string{
+		"v=DMARC1; p=none",
+		"v=spf1 ip4:2001:db8::1",
+		"v=spf1 ip6:192.0.2.1",
+		"v=spf1 include",
+		"v=spf1 all:example.com",
+		"v=spf1 foo:example.com",
+		"v=spf1 redirect=a.example.com redirect=b.example.com",
+	} {
+		if _, err := ParseSPF(content); err == nil {
+			t.Errorf("ParseSPF(%q) expected to return an error", content)
+		}
+	}
+}
+
+func TestSPFRecord_Flatten(t *testing.T) {
+	resolver := &fakeSPFResolver{
+		txt: map[string][]string{
+			"_spf.example.net":  {"google-site-verification=abc", "v=spf1 ip4:198.51.100.1 include:_spf2.example.net a:mail.example.net/24 -ip4:203.0.113.1 ~all"},
+			"_spf2.example.net": {"v=spf1 ip6:2001:db8::/32 mx redirect=_spf3.example.net"},
+			"_spf3.example.net": {"v=spf1 ip4:198.51.100.1 -all"},
+		},
+		ips: map[string][]string{
+			"mail.example.net": {"203.0.113.77", "2001:db8::77"},
+			"mx.example.net":   {"203.0.113.25"},
+		},
+		mx: map[string][]string{"_spf2.example.net": {"mx.example.net"}},
+	}
+
+	record, _ := ParseSPF("v=spf1 ip4:192.0.2.1 ~include:_spf.example.net -all")
+	flattened, err := record.Flatten(context.Background(), resolver)
+	if err != nil {
+		t.Fatalf("Flatten() returned error: %v", err)
+	}
+
+	want := "v=spf1 ip4:192.0.2.1 ~ip4:198.51.100.1 ~ip6:2001:db8::/32 ~ip4:203.0.113.25 ~ip4:203.0.113.0/24 ~ip6:2001:db8::77 -all"
+	if got := flattened.String(); want != got {
+		t.Errorf("Flatten() returned `%v`, expected `%v`", got, want)
+	}
+	if want, got := 0, flattened.DNSLookups(); want != got {
+		t.Errorf("Flatten() returned a record with %v lookups, expected %v", got, want)
+	}
+
+	// The record is not modified.
+	if want, got := "v=spf1 ip4:192.0.2.1 ~include:_spf.example.net -all", record.String(); want != got {
+		t.Errorf("Flatten() modified the record to `%v`", got)
+	}
+}
+
+func TestSPFRecord_Flatten_Errors(t *testing.T) {
+	resolver := &fakeSPFResolver{
+		txt: map[string][]string{
+			"loop.example.net":     {"v=spf1 include:loop2.example.net -all"},
+			"loop2.example.net":    {"v=spf1 include:loop.example.net -all"},
+			"exists.example.net":   {"v=spf1 exists:%{i}.example.net -all"},
+			"multiple.example.net": {"v=spf1 -all", "v=spf1 ~all"},
+			"all.example.net":      {"v=spf1 +all"},
+			"none.example.net":     {"hello"},
+		},
+	}
+
+	tests := map[string]string{
+		"loop.example.net":     "includes itself",
+		"exists.example.net":   "can't be flattened",
+		"multiple.example.net": "multiple SPF records",
+		"all.example.net":      "authorizes all the addresses",
+		"none.example.net":     "has no SPF record",
+		"missing.example.net":  "no such host",
+	}
+	for domainName, message := range tests {
+		record := &SPFRecord{Mechanisms: []SPFMechanism{{Type: "include", Value: domainName}}}
+		_, err := record.Flatten(context.Background(), resolver)
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("Flatten() of %v expected to return an error containing `%v`, got `%v`", domainName, message, err)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package dnsimple
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// emailDNSFixtures simulates the records of the zone example.com.
+type emailDNSFixtures struct {
+	mu      sync.Mutex
+	nextID  int64
+	records map[int64]ZoneRecord
+
+	// The record names for which the creation of a record fails.
+	failures map[string]bool
+
+	requests []string
+}
+
+func setupEmailDNSFixtures(t *testing.T, records ...ZoneRecord) *emailDNSFixtures {
+	f := &emailDNSFixtures{nextID: 100, records: map[int64]ZoneRecord{}, failures: map[string]bool{}}
+	for _, record := range records {
+		f.records[record.ID] = record
+	}
+
+	mux.HandleFunc("/v2/1010/zones/example.com/records", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		switch r.Method {
+		case "GET":
+			f.requests = append(f.requests, "LIST")
+			records := []ZoneRecord{}
+			for _, record := range f.records {
+				records = append(records, record)
+			}
+			sort.Sort(zoneRecordsByID(records))
+			writeData(w, r, http.StatusOK, records)
+		case "POST":
+			var record ZoneRecord
+			json.NewDecoder(r.Body).Decode(&record)
+			f.requests = append(f.requests, "CREATE "+record.Type+" "+record.Name)
+			if f.failures[record.Name] {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"message":"Validation failed"}`))
+				return
+			}
+			record.ID, record.ZoneID = f.nextID, "example.com"
+			f.nextID++
+			f.records[record.ID] = record
+			writeData(w, r, http.StatusCreated, record)
+		default:
+			t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+		}
+	})
+
+	mux.HandleFunc("/v2/1010/zones/example.com/records/", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if r.Method != "DELETE" {
+			t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+			return
+		}
+		id, _ := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/v2/1010/zones/example.com/records/"), 10, 64)
+		f.requests = append(f.requests, "DELETE "+strconv.FormatInt(id, 10))
+		delete(f.records, id)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return f
+}
+
+func TestEmailDNS_Records(t *testing.T) {
+	spf, _ := ParseSPF("v=spf1 mx -all")
+	config := &EmailDNS{
+		MX:    []MailExchanger{{Host: "MX1.example.com.", Priority: 10}},
+		SPF:   spf,
+		DKIM:  []*DKIMKey{{Selector: "mail", Record: "v=DKIM1; k=rsa; p=abc"}},
+		DMARC: &DMARCRecord{Policy: DMARCReject},
+		TTL:   600,
+	}
+
+	want := []ZoneRecord{
+		{Type: "MX", Name: "", Content: "mx1.example.com", Priority: 10, TTL: 600},
+		{Type: "TXT", Name: "", Content: "v=spf1 mx -all", TTL: 600},
+		{Type: "TXT", Name: "mail._domainkey", Content: "v=DKIM1; k=rsa; p=abc", TTL: 600},
+		{Type: "TXT", Name: "_dmarc", Content: "v=DMARC1; p=reject", TTL: 600},
+	}
+	if got := config.Records(); !reflect.DeepEqual(want, got) {
+		t.Errorf("EmailDNS.Records() returned `%+v`, expected `%+v`", got, want)
+	}
+}
+
+func TestApplyEmailDNS(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupEmailDNSFixtures(t,
+		ZoneRecord{ID: 1, Type: "MX", Name: "", Content: "mx1.example.com", Priority: 10},
+		ZoneRecord{ID: 2, Type: "MX", Name: "", Content: "old-mx.example.com", Priority: 20},
+		ZoneRecord{ID: 3, Type: "TXT", Name: "", Content: "v=spf1 include:old.example.net -all"},
+		ZoneRecord{ID: 4, Type: "TXT", Name: "", Content: "google-site-verification=abc"},
+		ZoneRecord{ID: 5, Type: "TXT", Name: "_dmarc", Content: `"v=DMARC1; p=reject"`},
+		ZoneRecord{ID: 6, Type: "TXT", Name: "old._domainkey", Content: "v=DKIM1; p=old"},
+		ZoneRecord{ID: 7, Type: "NS", Name: "", Content: "ns1.dnsimple.com", SystemRecord: true},
+	)
+
+	spf, _ := ParseSPF("v=spf1 mx -all")
+	config := &EmailDNS{
+		MX:    []MailExchanger{{Host: "mx1.example.com", Priority: 10}, {Host: "mx2.example.com", Priority: 20}},
+		SPF:   spf,
+		DKIM:  []*DKIMKey{{Selector: "mail", Record: "v=DKIM1; k=rsa; p=abc"}},
+		DMARC: &DMARCRecord{Policy: DMARCReject},
+	}
+
+	changes, err := ApplyEmailDNS(context.Background(), client, "1010", "example.com", config, &BulkOptions{Concurrency: 1})
+	if err != nil {
+		t.Fatalf("ApplyEmailDNS() returned error: %v", err)
+	}
+
+	var created []string
+	for _, record := range changes.Created {
+		created = append(created, record.Type+" "+record.Name+" "+record.Content)
+	}
+	wantCreated := []string{"MX  mx2.example.com", "TXT  v=spf1 mx -all", "TXT mail._domainkey v=DKIM1; k=rsa; p=abc"}
+	if !reflect.DeepEqual(wantCreated, created) {
+		t.Errorf("ApplyEmailDNS() created `%v`, expected `%v`", created, wantCreated)
+	}
+
+	var deleted, unchanged []int64
+	for _, record := range changes.Deleted {
+		deleted = append(deleted, record.ID)
+	}
+	for _, record := range changes.Unchanged {
+		unchanged = append(unchanged, record.ID)
+	}
+	if want := []int64{2, 3}; !reflect.DeepEqual(want, deleted) {
+		t.Errorf("ApplyEmailDNS() deleted `%v`, expected `%v`", deleted, want)
+	}
+	if want := []int64{1, 5}; !reflect.DeepEqual(want, unchanged) {
+		t.Errorf("ApplyEmailDNS() left unchanged `%v`, expected `%v`", unchanged, want)
+	}
+
+	// The records are created before the previous ones are deleted.
+	requests := f.requests
+	for len(requests) > 0 && requests[0] == "LIST" {
+		requests = requests[1:]
+	}
+	if want, got := "CREATE MX ", requests[0]; want != got {
+		t.Errorf("ApplyEmailDNS() first change expected to be `%v`, got `%v`", want, got)
+	}
+	if _, ok := f.records[6]; !ok {
+		t.Errorf("ApplyEmailDNS() expected to keep the DKIM records of the other selectors")
+	}
+}
+
+func TestApplyEmailDNS_CreateFailure(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	f := setupEmailDNSFixtures(t, ZoneRecord{ID: 1, Type: "TXT", Name: "_dmarc", Content: "v=DMARC1; p=none"})
+	f.failures["_dmarc"] = true
+
+	changes, err := ApplyEmailDNS(context.Background(), client, "1010", "example.com", &EmailDNS{DMARC: &DMARCRecord{Policy: DMARCReject}}, nil)
+	if err == nil {
+		t.Fatalf("ApplyEmailDNS() expected to return an error")
+	}
+	if want, got := 0, len(changes.Deleted); want != got {
+		t.Errorf("ApplyEmailDNS() deleted %v records, expected %v", got, want)
+	}
+	if _, ok := f.records[1]; !ok {
+		t.Errorf("ApplyEmailDNS() expected to keep the current DMARC record after a failed creation")
+	}
+}
+
+func TestAuditEmailDNS(t *testing.T) {
+	longText := strings.Repeat("a", 300)
+
+	issues := AuditEmailDNS("example.com", []ZoneRecord{
+		{Type: "MX", Name: "", Content: "192.0.2.1"},
+		{Type: "MX", Name: "", Content: "mail.example.com"},
+		{Type: "CNAME", Name: "mail", Content: "mx.example.net"},
+		{Type: "MX", Name: "ok", Content: "mx.example.net"},
+		{Type: "TXT", Name: "", Content: "v=spf1 include:a.example.net -all"},
+		{Type: "TXT", Name: "", Content: "v=spf1 +all"},
+		{Type: "TXT", Name: "www", Content: "v=spf1 mx"},
+		{Type: "TXT", Name: "bulk", Content: "v=spf1 include:a include:b include:c include:d include:e include:f a mx ptr exists:x redirect=y"},
+		{Type: "TXT", Name: "bad", Content: "v=spf1 ip4:999.0.0.1 -all"},
+		{Type: "SPF", Name: "", Content: "v=spf1 -all"},
+		{Type: "TXT", Name: "long", Content: longText},
+		{Type: "TXT", Name: "split", Content: `"` + longText[:255] + `" "` + longText[255:] + `"`},
+		{Type: "TXT", Name: "_dmarc", Content: "v=DMARC1; p=reject"},
+		{Type: "TXT", Name: "_dmarc", Content: "v=DMARC1; p=none"},
+		{Type: "TXT", Name: "_dmarc.sub", Content: "v=DMARC1; p=block"},
+		{Type: "TXT", Name: "", Content: "v=DMARC1; p=none"},
+		{Type: "TXT", Name: "mail._domainkey", Content: "v=DKIM1; k=rsa"},
+		{Type: "TXT", Name: "revoked._domainkey", Content: "v=DKIM1; p="},
+	})
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		"@ MX: points to the IP address 192.0.2.1 instead of a host name",
+		"@ MX: points to mail.example.com, which is a CNAME record",
+		"@ TXT: has an SPF policy authorizing every sender with +all",
+		"www TXT: has an SPF policy without an all mechanism",
+		"bulk TXT: has an SPF policy requiring 11 DNS lookups, more than 10",
+		"bad TXT: has an invalid SPF policy: SPF mechanism ip4:999.0.0.1 has an invalid address",
+		"@ SPF: uses the deprecated SPF record type, publish the policy in a TXT record",
+		"long TXT: has a character string of 300 bytes, longer than 255",
+		"_dmarc.sub TXT: has an invalid DMARC policy: invalid DMARC policy block",
+		"@ TXT: has a DMARC policy outside of a _dmarc record",
+		"mail._domainkey TXT: has a DKIM record without a public key",
+		"@ TXT: has 2 SPF policies, which fails the SPF checks",
+		"_dmarc TXT: has 2 DMARC policies, which disables DMARC",
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("AuditEmailDNS() returned:\n%v\nexpected:\n%v", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestAuditZoneEmailDNS(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	setupEmailDNSFixtures(t,
+		ZoneRecord{ID: 1, Type: "TXT", Name: "", Content: "v=spf1 -all"},
+		ZoneRecord{ID: 2, Type: "TXT", Name: "", Content: "v=spf1 mx -all"},
+	)
+
+	issues, err := AuditZoneEmailDNS(client, "1010", "example.com")
+	if err != nil {
+		t.Fatalf("AuditZoneEmailDNS() returned error: %v", err)
+	}
+	want := []EmailDNSIssue{{Name: "", Type: "TXT", Message: "has 2 SPF policies, which fails the SPF checks"}}
+	if !reflect.DeepEqual(want, issues) {
+		t.Errorf("AuditZoneEmailDNS() returned `%+v`, expected `%+v`", issues, want)
+	}
+}
@@ -0,0 +1,631 @@
+package dnsimple
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// importableRecordTypes are the record types that can be created in a DNSimple zone.
+var importableRecordTypes = map[string]bool{
+	"A":     true,
+	"AAAA":  true,
+	"ALIAS": true,
+	"CAA":   true,
+	"CNAME": true,
+	"HINFO": true,
+	"MX":    true,
+	"NAPTR": true,
+	"NS":    true,
+	"PTR":   true,
+	"SPF":   true,
+	"SRV":   true,
+	"SSHFP": true,
+	"TXT":   true,
+	"URL":   true,
+}
+
+// ZoneImport represents the result of converting a zone exported from another
+// DNS provider into records that can be passed to ZonesService.CreateRecord.
+type ZoneImport struct {
+	// The records that were converted.
+	Records []ZoneRecord
+
+	// The entries of the input that could not be converted.
+	Diagnostics []ZoneImportDiagnostic
+}
+
+// ZoneImportDiagnostic describes an entry of the input that could not be converted.
+type ZoneImportDiagnostic struct {
+	// The line of the input the entry starts at.
+	// For JSON formats this is the position of the entry in the record list, starting from 1.
+	// For CSV this is the number of the row, counting the header.
+	Line int
+
+	// The raw entry, as found in the input.
+	Input string
+
+	// A human-readable description of the problem.
+	Message string
+}
+
+// Error implements the error interface.
+func (d *ZoneImportDiagnostic) Error() string {
+	return fmt.Sprintf("line %d: %v", d.Line, d.Message)
+}
+
+func (zi *ZoneImport) add(line int, input string, record importedRecord, origin string) {
+	zoneRecord, err := record.normalize(origin)
+	if err != nil {
+		zi.diagnose(line, input, err.Error())
+		return
+	}
+	zi.Records = append(zi.Records, zoneRecord)
+}
+
+func (zi *ZoneImport) diagnose(line int, input string, message string) {
+	zi.Diagnostics = append(zi.Diagnostics, ZoneImportDiagnostic{Line: line, Input: input, Message: message})
+}
+
+// importedRecord is a record as read from a provider export, before normalization.
+type importedRecord struct {
+	// The record name. It is relative to the zone,
+	// unless it ends with a dot.
+	Name     string
+	Type     string
+	Content  string
+	TTL      int
+	Priority int
+}
+
+// normalize converts the imported record into a ZoneRecord for the zone origin.
+//
+// Names are made relative to the origin, using an empty string for the apex.
+// Types that can't be created in a DNSimple zone result in an error.
+func (r importedRecord) normalize(origin string) (ZoneRecord, error) {
+	record := ZoneRecord{Type: strings.ToUpper(r.Type), TTL: r.TTL, Priority: r.Priority}
+
+	name, err := importRecordName(origin, r.Name)
+	if err != nil {
+		return record, err
+	}
+	record.Name = name
+
+	switch {
+	case record.Type == "SOA":
+		return record, fmt.Errorf("SOA records are managed by DNSimple")
+	case record.Type == "NS" && record.Name == "":
+		return record, fmt.Errorf("apex NS records are managed by DNSimple")
+	case !importableRecordTypes[record.Type]:
+		return record, fmt.Errorf("unsupported record type %v", record.Type)
+	}
+
+	content := strings.TrimSpace(r.Content)
+	switch record.Type {
+	case "A":
+		if ip := net.ParseIP(content); ip == nil || ip.To4() == nil {
+			return record, fmt.Errorf("invalid IPv4 address %q", content)
+		}
+	case "AAAA":
+		if ip := net.ParseIP(content); ip == nil || ip.To4() != nil {
+			return record, fmt.Errorf("invalid IPv6 address %q", content)
+		}
+	case "ALIAS", "CNAME", "NS", "PTR":
+		content = strings.TrimSuffix(content, ".")
+	case "MX":
+		if content, err = splitImportPriority(&record, content, 2); err != nil {
+			return record, err
+		}
+		content = strings.TrimSuffix(content, ".")
+	case "SRV":
+		if content, err = splitImportPriority(&record, content, 4); err != nil {
+			return record, err
+		}
+		content = strings.TrimSuffix(content, ".")
+	case "SPF", "TXT":
+		content = unquoteImportText(content)
+	}
+
+	if content == "" {
+		return record, fmt.Errorf("missing content for %v record", record.Type)
+	}
+	record.Content = content
+
+	return record, nil
+}
+
+// importRecordName converts name into a name relative to the origin.
+func importRecordName(origin string, name string) (string, error) {
+	origin = strings.ToLower(strings.TrimSuffix(origin, "."))
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	if name == "" || name == "@" {
+		return "", nil
+	}
+	if !strings.HasSuffix(name, ".") {
+		return name, nil
+	}
+
+	name = strings.TrimSuffix(name, ".")
+	switch {
+	case name == origin:
+		return "", nil
+	case strings.HasSuffix(name, "."+origin):
+		return strings.TrimSuffix(name, "."+origin), nil
+	}
+
+	return "", fmt.Errorf("name %v is outside of the zone %v", name, origin)
+}
+
+// splitImportPriority extracts the leading priority from the content of a record
+// whose content has the given number of fields when the priority is included.
+func splitImportPriority(record *ZoneRecord, content string, fields int) (string, error) {
+	parts := strings.Fields(content)
+	if len(parts) != fields {
+		if len(parts) == fields-1 {
+			return content, nil
+		}
+		return "", fmt.Errorf("invalid %v record content %q", record.Type, content)
+	}
+
+	priority, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("invalid %v record priority %q", record.Type, parts[0])
+	}
+	record.Priority = priority
+
+	return strings.Join(parts[1:], " "), nil
+}
+
+// unquoteImportText joins the quoted character strings of a TXT record.
+// Content that isn't quoted is returned unchanged.
+func unquoteImportText(content string) string {
+	if !strings.HasPrefix(content, `"`) {
+		return content
+	}
+
+	var text bytes.Buffer
+	inQuote := false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+		case c == '\\' && i+3 < len(content) && isDigits(content[i+1:i+4]):
+			value, _ := strconv.Atoi(content[i+1 : i+4])
+			text.WriteByte(byte(value))
+			i += 3
+		case c == '\\' && i+1 < len(content):
+			i++
+			text.WriteByte(content[i])
+		case inQuote:
+			text.WriteByte(c)
+		}
+	}
+
+	return text.String()
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// ImportBINDZone converts a BIND zone file into records for the zone origin.
+//
+// The $ORIGIN and $TTL directives, multi-line records and relative names are supported.
+func ImportBINDZone(origin string, r io.Reader) (*ZoneImport, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	zi := &ZoneImport{}
+	currentOrigin := strings.TrimSuffix(origin, ".") + "."
+	owner := currentOrigin
+	defaultTTL := 0
+
+	for _, line := range tokenizeBINDZone(string(data)) {
+		tokens := line.tokens
+
+		switch strings.ToUpper(tokens[0]) {
+		case "$ORIGIN":
+			if len(tokens) < 2 {
+				zi.diagnose(line.number, line.input, "missing $ORIGIN value")
+				continue
+			}
+			currentOrigin = qualifyBINDName(tokens[1], currentOrigin)
+			continue
+		case "$TTL":
+			ttl, ok := parseBINDTTL(safeToken(tokens, 1))
+			if !ok {
+				zi.diagnose(line.number, line.input, "invalid $TTL value")
+				continue
+			}
+			defaultTTL = ttl
+			continue
+		case "$INCLUDE", "$GENERATE":
+			zi.diagnose(line.number, line.input, fmt.Sprintf("unsupported directive %v", tokens[0]))
+			continue
+		}
+
+		if !line.inheritOwner {
+			owner, tokens = qualifyBINDName(tokens[0], currentOrigin), tokens[1:]
+		}
+
+		ttl := defaultTTL
+		for len(tokens) > 0 {
+			if value, ok := parseBINDTTL(tokens[0]); ok {
+				ttl = value
+			} else if !isBINDClass(tokens[0]) {
+				break
+			}
+			tokens = tokens[1:]
+		}
+
+		if len(tokens) == 0 {
+			zi.diagnose(line.number, line.input, "missing record type")
+			continue
+		}
+
+		recordType, rdata := strings.ToUpper(tokens[0]), tokens[1:]
+		if host := bindHostField(recordType); host >= 0 && host < len(rdata) {
+			rdata[host] = qualifyBINDName(rdata[host], currentOrigin)
+		}
+
+		record := importedRecord{Name: owner, Type: recordType, Content: strings.Join(rdata, " "), TTL: ttl}
+		zi.add(line.number, line.input, record, origin)
+	}
+
+	return zi, nil
+}
+
+// bindLine is a logical line of a BIND zone file.
+type bindLine struct {
+	number       int
+	input        string
+	tokens       []string
+	inheritOwner bool
+}
+
+// tokenizeBINDZone splits a zone file into logical lines,
+// joining the lines enclosed in parentheses and removing the comments.
+func tokenizeBINDZone(data string) []bindLine {
+	var (
+		lines   []bindLine
+		current bindLine
+		token   bytes.Buffer
+		input   bytes.Buffer
+		number  = 1
+		depth   = 0
+		inQuote = false
+		atStart = true
+	)
+
+	flushToken := func() {
+		if token.Len() > 0 {
+			current.tokens = append(current.tokens, token.String())
+			token.Reset()
+		}
+	}
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if atStart {
+			current = bindLine{number: number, inheritOwner: c == ' ' || c == '\t'}
+			input.Reset()
+			atStart = false
+		}
+
+		switch {
+		case inQuote:
+			token.WriteByte(c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				token.WriteByte(data[i])
+			} else if c == '"' {
+				inQuote = false
+			}
+		case c == '"':
+			inQuote = true
+			token.WriteByte(c)
+		case c == ';':
+			for i+1 < len(data) && data[i+1] != '\n' {
+				i++
+			}
+			continue
+		case c == '(':
+			flushToken()
+			depth++
+		case c == ')':
+			flushToken()
+			if depth > 0 {
+				depth--
+			}
+		case c == '\n':
+			flushToken()
+			number++
+			if depth == 0 {
+				if len(current.tokens) > 0 {
+					current.input = strings.TrimSpace(input.String())
+					lines = append(lines, current)
+				}
+				atStart = true
+				continue
+			}
+		case c == ' ' || c == '\t' || c == '\r':
+			flushToken()
+		default:
+			token.WriteByte(c)
+		}
+
+		input.WriteByte(c)
+	}
+
+	flushToken()
+	if !atStart && len(current.tokens) > 0 {
+		current.input = strings.TrimSpace(input.String())
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+// qualifyBINDName returns the fully qualified form of a BIND name.
+func qualifyBINDName(name string, origin string) string {
+	switch {
+	case name == "@":
+		return origin
+	case strings.HasSuffix(name, "."):
+		return name
+	}
+	return name + "." + origin
+}
+
+// bindHostField returns the position of the host name in the rdata of the given type,
+// or -1 if the type has no host name.
+func bindHostField(recordType string) int {
+	switch recordType {
+	case "ALIAS", "CNAME", "NS", "PTR":
+		return 0
+	case "MX":
+		return 1
+	case "SRV":
+		return 3
+	}
+	return -1
+}
+
+func isBINDClass(token string) bool {
+	switch strings.ToUpper(token) {
+	case "IN", "CH", "CS", "HS":
+		return true
+	}
+	return false
+}
+
+// parseBINDTTL parses a TTL in seconds, or with the BIND unit suffixes (eg. 1h30m).
+func parseBINDTTL(token string) (int, bool) {
+	if token == "" || token[0] < '0' || token[0] > '9' {
+		return 0, false
+	}
+
+	total, value := 0, 0
+	for i, c := range strings.ToLower(token) {
+		if c >= '0' && c <= '9' {
+			value = value*10 + int(c-'0')
+			if i == len(token)-1 {
+				total += value
+			}
+			continue
+		}
+
+		switch c {
+		case 's':
+		case 'm':
+			value *= 60
+		case 'h':
+			value *= 3600
+		case 'd':
+			value *= 86400
+		case 'w':
+			value *= 604800
+		default:
+			return 0, false
+		}
+		total, value = total+value, 0
+	}
+
+	return total, true
+}
+
+func safeToken(tokens []string, i int) string {
+	if i < len(tokens) {
+		return tokens[i]
+	}
+	return ""
+}
+
+// route53RecordSets represents the output of the Route 53 ListResourceRecordSets API.
+type route53RecordSets struct {
+	ResourceRecordSets []struct {
+		Name            string
+		Type            string
+		TTL             int
+		ResourceRecords []struct {
+			Value string
+		}
+		AliasTarget *struct {
+			DNSName string
+		}
+	}
+}
+
+// ImportRoute53Zone converts the JSON output of the Route 53 ListResourceRecordSets API
+// into records for the zone origin.
+//
+// Alias record sets are converted into ALIAS records.
+func ImportRoute53Zone(origin string, r io.Reader) (*ZoneImport, error) {
+	export := &route53RecordSets{}
+	if err := json.NewDecoder(r).Decode(export); err != nil {
+		return nil, err
+	}
+
+	zi := &ZoneImport{}
+	for i, set := range export.ResourceRecordSets {
+		name := unescapeRoute53Name(set.Name)
+		input := fmt.Sprintf("%v %v", set.Name, set.Type)
+
+		if set.AliasTarget != nil {
+			record := importedRecord{Name: name, Type: "ALIAS", Content: set.AliasTarget.DNSName}
+			zi.add(i+1, input, record, origin)
+			continue
+		}
+
+		for _, rr := range set.ResourceRecords {
+			record := importedRecord{Name: name, Type: set.Type, Content: rr.Value, TTL: set.TTL}
+			zi.add(i+1, input, record, origin)
+		}
+	}
+
+	return zi, nil
+}
+
+// unescapeRoute53Name replaces the octal escapes Route 53 uses in names (eg. \052 for *).
+func unescapeRoute53Name(name string) string {
+	var unescaped bytes.Buffer
+	for i := 0; i < len(name); i++ {
+		if name[i] == '\\' && i+3 < len(name) {
+			if value, err := strconv.ParseUint(name[i+1:i+4], 8, 8); err == nil {
+				unescaped.WriteByte(byte(value))
+				i += 3
+				continue
+			}
+		}
+		unescaped.WriteByte(name[i])
+	}
+	return unescaped.String()
+}
+
+// cloudflareRecord represents a DNS record in a Cloudflare export.
+type cloudflareRecord struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Content  string `json:"content"`
+	TTL      int    `json:"ttl"`
+	Priority int    `json:"priority"`
+}
+
+// ImportCloudflareZone converts the JSON output of the Cloudflare DNS records API
+// into records for the zone origin.
+//
+// Both the full API response and a bare list of records are accepted.
+func ImportCloudflareZone(origin string, r io.Reader) (*ZoneImport, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []cloudflareRecord
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		err = json.Unmarshal(data, &records)
+	} else {
+		export := &struct {
+			Result []cloudflareRecord `json:"result"`
+		}{}
+		err = json.Unmarshal(data, export)
+		records = export.Result
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	zi := &ZoneImport{}
+	for i, cf := range records {
+		// Cloudflare uses 1 as the automatic TTL.
+		ttl := cf.TTL
+		if ttl == 1 {
+			ttl = 0
+		}
+
+		record := importedRecord{Name: cf.Name + ".", Type: cf.Type, Content: cf.Content, TTL: ttl, Priority: cf.Priority}
+		zi.add(i+1, fmt.Sprintf("%v %v %v", cf.Name, cf.Type, cf.Content), record, origin)
+	}
+
+	return zi, nil
+}
+
+// ImportCSVZone converts CSV records into records for the zone origin.
+//
+// The first row must be a header with the name, type and content columns,
+// and optionally the ttl and priority columns. Names are relative to the zone,
+// unless they end with a dot.
+func ImportCSVZone(origin string, r io.Reader) (*ZoneImport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range []string{"name", "type", "content"} {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("missing CSV column %v", column)
+		}
+	}
+
+	zi := &ZoneImport{}
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		field := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		record := importedRecord{Name: field("name"), Type: field("type"), Content: field("content")}
+		input := strings.Join(row, ",")
+
+		if value := field("ttl"); value != "" {
+			if record.TTL, err = strconv.Atoi(value); err != nil {
+				zi.diagnose(line, input, fmt.Sprintf("invalid TTL %q", value))
+				continue
+			}
+		}
+		if value := field("priority"); value != "" {
+			if record.Priority, err = strconv.Atoi(value); err != nil {
+				zi.diagnose(line, input, fmt.Sprintf("invalid priority %q", value))
+				continue
+			}
+		}
+
+		zi.add(line, input, record, origin)
+	}
+
+	return zi, nil
+}
@@ -0,0 +1,189 @@
+package dnsimple
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportRecordName(t *testing.T) {
+	cases := []struct {
+		name string
+		want string
+	}{
+		{"", ""},
+		{"@", ""},
+		{"example.com.", ""},
+		{"WWW.Example.com.", "www"},
+		{"a.b.example.com.", "a.b"},
+		{"www", "www"},
+	}
+
+	for _, c := range cases {
+		got, err := importRecordName("example.com", c.name)
+		if err != nil {
+			t.Errorf("importRecordName(%q) returned error: %v", c.name, err)
+		}
+		if got != c.want {
+			t.Errorf("importRecordName(%q) = %q, want %q", c.name, got, c.want)
+		}
+	}
+
+	if _, err := importRecordName("example.com", "www.example.org."); err == nil {
+		t.Errorf("importRecordName() expected to return an error for a name outside of the zone")
+	}
+}
+
+func TestImportBINDZone(t *testing.T) {
+	zone := `$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns1.dnsimple.com. admin.dnsimple.com. (
+		1453132552 ; serial
+		86400 7200 604800 300 )
+@	IN	NS	ns1.dnsimple.com.
+@	300	IN	A	192.0.2.1
+	IN	MX	10 mail
+www		CNAME	@
+mail	IN	AAAA	2001:db8::1
+txt	IN	TXT	"v=spf1 include:_spf.google.com" " ~all" ; a comment
+_sip._tcp	SRV	10 20 5060 sip.example.net.
+bad	IN	A	not-an-ip
+loc	IN	LOC	52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000m 10m
+$ORIGIN sub.example.com.
+host	1d	IN	A	192.0.2.2
+`
+
+	zi, err := ImportBINDZone("example.com", strings.NewReader(zone))
+	if err != nil {
+		t.Fatalf("ImportBINDZone() returned error: %v", err)
+	}
+
+	want := []ZoneRecord{
+		{Name: "", Type: "A", Content: "192.0.2.1", TTL: 300},
+		{Name: "", Type: "MX", Content: "mail.example.com", TTL: 3600, Priority: 10},
+		{Name: "www", Type: "CNAME", Content: "example.com", TTL: 3600},
+		{Name: "mail", Type: "AAAA", Content: "2001:db8::1", TTL: 3600},
+		{Name: "txt", Type: "TXT", Content: "v=spf1 include:_spf.google.com ~all", TTL: 3600},
+		{Name: "_sip._tcp", Type: "SRV", Content: "20 5060 sip.example.net", TTL: 3600, Priority: 10},
+		{Name: "host.sub", Type: "A", Content: "192.0.2.2", TTL: 86400},
+	}
+	if !reflect.DeepEqual(want, zi.Records) {
+		t.Errorf("ImportBINDZone() records = %+v, want %+v", zi.Records, want)
+	}
+
+	var lines []int
+	for _, diagnostic := range zi.Diagnostics {
+		lines = append(lines, diagnostic.Line)
+	}
+	if want := []int{3, 6, 13, 14}; !reflect.DeepEqual(want, lines) {
+		t.Errorf("ImportBINDZone() diagnostics lines = %v, want %v (%+v)", lines, want, zi.Diagnostics)
+	}
+	if want, got := "unsupported record type LOC", zi.Diagnostics[3].Message; want != got {
+		t.Errorf("ImportBINDZone() diagnostic message expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestImportRoute53Zone(t *testing.T) {
+	export := `{
+  "ResourceRecordSets": [
+    {"Name": "example.com.", "Type": "SOA", "TTL": 900, "ResourceRecords": [{"Value": "ns-1.awsdns-1.com. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400"}]},
+    {"Name": "example.com.", "Type": "MX", "TTL": 300, "ResourceRecords": [{"Value": "10 mx1.example.com."}, {"Value": "20 mx2.example.com."}]},
+    {"Name": "example.com.", "Type": "A", "AliasTarget": {"HostedZoneId": "Z2FDTNDATAQYW2", "DNSName": "d111111abcdef8.cloudfront.net.", "EvaluateTargetHealth": false}},
+    {"Name": "\\052.example.com.", "Type": "TXT", "TTL": 60, "ResourceRecords": [{"Value": "\"hello world\""}]}
+  ]
+}`
+
+	zi, err := ImportRoute53Zone("example.com", strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("ImportRoute53Zone() returned error: %v", err)
+	}
+
+	want := []ZoneRecord{
+		{Name: "", Type: "MX", Content: "mx1.example.com", TTL: 300, Priority: 10},
+		{Name: "", Type: "MX", Content: "mx2.example.com", TTL: 300, Priority: 20},
+		{Name: "", Type: "ALIAS", Content: "d111111abcdef8.cloudfront.net"},
+		{Name: "*", Type: "TXT", Content: "hello world", TTL: 60},
+	}
+	if !reflect.DeepEqual(want, zi.Records) {
+		t.Errorf("ImportRoute53Zone() records = %+v, want %+v", zi.Records, want)
+	}
+
+	if want, got := 1, len(zi.Diagnostics); want != got {
+		t.Fatalf("ImportRoute53Zone() expected to return %v diagnostics, got %v", want, got)
+	}
+	if want, got := 1, zi.Diagnostics[0].Line; want != got {
+		t.Errorf("ImportRoute53Zone() diagnostic Line expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestImportCloudflareZone(t *testing.T) {
+	export := `{"result": [
+  {"type": "A", "name": "example.com", "content": "192.0.2.1", "ttl": 1, "proxied": true},
+  {"type": "MX", "name": "example.com", "content": "mail.example.com", "ttl": 3600, "priority": 5},
+  {"type": "CNAME", "name": "www.example.com", "content": "example.com", "ttl": 120},
+  {"type": "A", "name": "other.example.org", "content": "192.0.2.2", "ttl": 1}
+], "success": true}`
+
+	zi, err := ImportCloudflareZone("example.com", strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("ImportCloudflareZone() returned error: %v", err)
+	}
+
+	want := []ZoneRecord{
+		{Name: "", Type: "A", Content: "192.0.2.1"},
+		{Name: "", Type: "MX", Content: "mail.example.com", TTL: 3600, Priority: 5},
+		{Name: "www", Type: "CNAME", Content: "example.com", TTL: 120},
+	}
+	if !reflect.DeepEqual(want, zi.Records) {
+		t.Errorf("ImportCloudflareZone() records = %+v, want %+v", zi.Records, want)
+	}
+
+	if want, got := 1, len(zi.Diagnostics); want != got {
+		t.Fatalf("ImportCloudflareZone() expected to return %v diagnostics, got %v", want, got)
+	}
+	if want, got := 4, zi.Diagnostics[0].Line; want != got {
+		t.Errorf("ImportCloudflareZone() diagnostic Line expected to be `%v`, got `%v`", want, got)
+	}
+
+	zi, err = ImportCloudflareZone("example.com", strings.NewReader(`[{"type": "TXT", "name": "example.com", "content": "hello"}]`))
+	if err != nil {
+		t.Fatalf("ImportCloudflareZone() returned error: %v", err)
+	}
+	if want, got := 1, len(zi.Records); want != got {
+		t.Errorf("ImportCloudflareZone() expected to return %v records, got %v", want, got)
+	}
+}
+
+func TestImportCSVZone(t *testing.T) {
+	export := `Name,Type,Content,TTL,Priority
+,A,192.0.2.1,600,
+www,CNAME,example.com.,,
+,MX,mail.example.com,3600,10
+api,A,192.0.2.3,soon,
+`
+
+	zi, err := ImportCSVZone("example.com", strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("ImportCSVZone() returned error: %v", err)
+	}
+
+	want := []ZoneRecord{
+		{Name: "", Type: "A", Content: "192.0.2.1", TTL: 600},
+		{Name: "www", Type: "CNAME", Content: "example.com"},
+		{Name: "", Type: "MX", Content: "mail.example.com", TTL: 3600, Priority: 10},
+	}
+	if !reflect.DeepEqual(want, zi.Records) {
+		t.Errorf("ImportCSVZone() records = %+v, want %+v", zi.Records, want)
+	}
+
+	if want, got := 1, len(zi.Diagnostics); want != got {
+		t.Fatalf("ImportCSVZone() expected to return %v diagnostics, got %v", want, got)
+	}
+	if want, got := 5, zi.Diagnostics[0].Line; want != got {
+		t.Errorf("ImportCSVZone() diagnostic Line expected to be `%v`, got `%v`", want, got)
+	}
+
+	if _, err := ImportCSVZone("example.com", strings.NewReader("name,content\n")); err == nil {
+		t.Errorf("ImportCSVZone() expected to return an error when a column is missing")
+	}
+}
@@ -0,0 +1,272 @@
+package dnsimple
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultBulkConcurrency is the number of requests a bulk operation
+// keeps in flight when no concurrency is specified.
+const defaultBulkConcurrency = 4
+
+// rateLimitMinPause is the minimum time a bulk operation waits
+// before retrying a request rejected by the rate limit.
+var rateLimitMinPause = time.Second
+
+// ErrBulkSkipped is the error reported for the items of a bulk operation
+// that were never sent because the operation stopped early.
+var ErrBulkSkipped = errors.New("dnsimple: bulk operation stopped before this item was processed")
+
+// BulkOptions specifies the optional parameters you can provide
+// to customize the bulk operations, such as the ZonesService bulk record methods.
+type BulkOptions struct {
+	// The maximum number of requests in flight at the same time.
+	// Defaults to 4.
+	Concurrency int
+
+	// Set to true to stop sending new requests after the first failure.
+	// By default every item is processed and all the errors are collected.
+	StopOnError bool
+}
+
+func (o *BulkOptions) concurrency() int {
+	if o == nil || o.Concurrency < 1 {
+		return defaultBulkConcurrency
+	}
+	return o.Concurrency
+}
+
+func (o *BulkOptions) stopOnError() bool {
+	return o != nil && o.StopOnError
+}
+
+// ZoneRecordBulkResult represents the outcome of a single item in a bulk record operation.
+type ZoneRecordBulkResult struct {
+	// The record returned by the API, if any.
+	Record *ZoneRecord
+
+	// The error returned for this item, if any.
+	Err error
+}
+
+// CreateRecords creates the given records in a zone using a bounded pool of workers.
+//
+// The results are returned in the same order as the records. The returned error
+// is the first error encountered in input order, or the context error if the
+// operation was cancelled.
+func (s *ZonesService) CreateRecords(ctx context.Context, accountID string, zoneName string, records []ZoneRecord, options *BulkOptions) ([]ZoneRecordBulkResult, error) {
+	results := make([]ZoneRecordBulkResult, len(records))
+
+	errs, err := runBulk(ctx, len(records), options, func(i int) (*http.Response, error) {
+		recordResponse, err := s.CreateRecord(accountID, zoneName, records[i])
+		if err != nil {
+			return nil, err
+		}
+		results[i].Record = recordResponse.Data
+		return recordResponse.HttpResponse, nil
+	})
+
+	for i := range results {
+		results[i].Err = errs[i]
+	}
+	return results, err
+}
+
+// UpdateRecords updates the given records in a zone using a bounded pool of workers.
+// Each record is identified by its ID.
+//
+// The results are returned in the same order as the records. The returned error
+// is the first error encountered in input order, or the context error if the
+// operation was cancelled.
+func (s *ZonesService) UpdateRecords(ctx context.Context, accountID string, zoneName string, records []ZoneRecord, options *BulkOptions) ([]ZoneRecordBulkResult, error) {
+	results := make([]ZoneRecordBulkResult, len(records))
+
+	errs, err := runBulk(ctx, len(records), options, func(i int) (*http.Response, error) {
+		recordResponse, err := s.UpdateRecord(accountID, zoneName, records[i].ID, records[i])
+		if err != nil {
+			return nil, err
+		}
+		results[i].Record = recordResponse.Data
+		return recordResponse.HttpResponse, nil
+	})
+
+	for i := range results {
+		results[i].Err = errs[i]
+	}
+	return results, err
+}
+
+// DeleteRecords PERMANENTLY deletes the given records from a zone using a bounded pool of workers.
+//
+// The results are returned in the same order as the record IDs. The returned error
+// is the first error encountered in input order, or the context error if the
+// operation was cancelled.
+func (s *ZonesService) DeleteRecords(ctx context.Context, accountID string, zoneName string, recordIDs []int64, options *BulkOptions) ([]ZoneRecordBulkResult, error) {
+	results := make([]ZoneRecordBulkResult, len(recordIDs))
+
+	errs, err := runBulk(ctx, len(recordIDs), options, func(i int) (*http.Response, error) {
+		recordResponse, err := s.DeleteRecord(accountID, zoneName, recordIDs[i])
+		if err != nil {
+			return nil, err
+		}
+		return recordResponse.HttpResponse, nil
+	})
+
+	for i := range results {
+		results[i].Err = errs[i]
+	}
+	return results, err
+}
+
+// runBulk calls do for each index in [0, n) on a bounded pool of workers,
+// and returns the error of each index along with the first error of the sent indexes
+// in index order.
+//
+// Requests rejected because the rate limit has been exceeded are retried
+// once the rate limit window is reset. When the operation stops early,
+// the indexes that were never sent report ErrBulkSkipped, or the context error
+// if the operation was cancelled by the caller.
+func runBulk(parent context.Context, n int, options *BulkOptions, do func(i int) (*http.Response, error)) ([]error, error) {
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	var (
+		limiter rateLimiter
+		wg      sync.WaitGroup
+		errs    = make([]error, n)
+		sent    = make([]bool, n)
+		jobs    = make(chan int)
+	)
+
+	for w := 0; w < options.concurrency() && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if limiter.wait(ctx) != nil {
+					continue
+				}
+
+				// Each index is owned by a single worker,
+				// so errs and sent don't need any locking.
+				sent[i] = true
+				errs[i] = limiter.do(ctx, func() (*http.Response, error) { return do(i) })
+
+				// A request rejected by the rate limit, and interrupted while waiting
+				// for the next window because the operation stopped early, was never processed.
+				if errs[i] != nil && errs[i] == ctx.Err() && parent.Err() == nil {
+					sent[i], errs[i] = false, nil
+					continue
+				}
+
+				if errs[i] != nil && options.stopOnError() {
+					cancel()
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	// When the caller cancels the operation, the context error explains
+	// why the remaining items were skipped and takes precedence. Otherwise
+	// the first failure of a sent item is the cause of the early stop,
+	// even when an item with a lower index was skipped.
+	firstErr := parent.Err()
+	var skippedErr error
+	for i := 0; i < n; i++ {
+		if !sent[i] {
+			errs[i] = ErrBulkSkipped
+			if err := parent.Err(); err != nil {
+				errs[i] = err
+			}
+			if skippedErr == nil {
+				skippedErr = errs[i]
+			}
+			continue
+		}
+		if firstErr == nil && errs[i] != nil {
+			firstErr = errs[i]
+		}
+	}
+	if firstErr == nil {
+		firstErr = skippedErr
+	}
+
+	return errs, firstErr
+}
+
+// rateLimiter pauses the bulk workers when the API rate limit has been exhausted.
+type rateLimiter struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// do calls fn and keeps retrying as long as the API rejects the request
+// because the rate limit has been exceeded.
+func (l *rateLimiter) do(ctx context.Context, fn func() (*http.Response, error)) error {
+	for {
+		resp, err := fn()
+
+		if errorResponse, ok := err.(*ErrorResponse); ok {
+			resp = errorResponse.HttpResponse
+		}
+		if resp != nil {
+			l.update(resp)
+		}
+
+		if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+			return err
+		}
+
+		if err := l.wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// wait blocks until the rate limit window is reset or the context is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	d := l.until.Sub(time.Now())
+	l.mu.Unlock()
+
+	return sleep(ctx, d)
+}
+
+// update pauses the workers until the end of the rate limit window
+// when the response reports that no requests are left.
+func (l *rateLimiter) update(resp *http.Response) {
+	r := &Response{HttpResponse: resp}
+	exhausted := resp.Header.Get("X-RateLimit-Remaining") != "" && r.RateLimitRemaining() == 0
+	if !exhausted && resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+
+	until := r.RateLimitReset()
+	if min := time.Now().Add(rateLimitMinPause); resp.StatusCode == http.StatusTooManyRequests && until.Before(min) {
+		until = min
+	}
+
+	l.mu.Lock()
+	if until.After(l.until) {
+		l.until = until
+	}
+	l.mu.Unlock()
+}
@@ -0,0 +1,283 @@
+package dnsimple
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestZonesService_CreateRecords(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/zones/example.com/records", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testHeaders(t, r)
+
+		record := ZoneRecord{}
+		json.NewDecoder(r.Body).Decode(&record)
+		record.ID = int64(len(record.Name))
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": record})
+	})
+
+	records := []ZoneRecord{
+		{Name: "a", Type: "A", Content: "127.0.0.1"},
+		{Name: "bb", Type: "A", Content: "127.0.0.2"},
+		{Name: "ccc", Type: "A", Content: "127.0.0.3"},
+	}
+
+	results, err := client.Zones.CreateRecords(context.Background(), "1010", "example.com", records, &BulkOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatalf("Zones.CreateRecords() returned error: %v", err)
+	}
+
+	if want, got := len(records), len(results); want != got {
+		t.Fatalf("Zones.CreateRecords() expected to return %v results, got %v", want, got)
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			t.Errorf("Zones.CreateRecords() result %v returned error: %v", i, result.Err)
+		}
+		if want, got := records[i].Name, result.Record.Name; want != got {
+			t.Errorf("Zones.CreateRecords() result %v Name expected to be `%v`, got `%v`", i, want, got)
+		}
+		if want, got := int64(i+1), result.Record.ID; want != got {
+			t.Errorf("Zones.CreateRecords() result %v ID expected to be `%v`, got `%v`", i, want, got)
+		}
+	}
+}
+
+func TestZonesService_CreateRecords_ContinueOnError(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/zones/example.com/records", func(w http.ResponseWriter, r *http.Request) {
+		record := ZoneRecord{}
+		json.NewDecoder(r.Body).Decode(&record)
+
+		fixture := "/api/createZoneRecord/created.http"
+		if record.Name == "invalid" {
+			fixture = "/api/validation-error.http"
+		}
+		httpResponse := httpResponseFixture(t, fixture)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	records := []ZoneRecord{{Name: "www"}, {Name: "invalid"}, {Name: "www"}, {Name: "invalid"}}
+
+	results, err := client.Zones.CreateRecords(context.Background(), "1010", "example.com", records, nil)
+	if err == nil {
+		t.Fatalf("Zones.CreateRecords() expected to return an error")
+	}
+	if err != results[1].Err {
+		t.Errorf("Zones.CreateRecords() expected to return the first error in input order, got %v", err)
+	}
+
+	for i, result := range results {
+		if wantErr := i%2 == 1; (result.Err != nil) != wantErr {
+			t.Errorf("Zones.CreateRecords() result %v error = %v, want error %v", i, result.Err, wantErr)
+		}
+	}
+}
+
+func TestZonesService_CreateRecords_StopOnError(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	var requests int32
+	mux.HandleFunc("/v2/1010/zones/example.com/records", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		httpResponse := httpResponseFixture(t, "/api/validation-error.http")
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	records := []ZoneRecord{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+
+	results, err := client.Zones.CreateRecords(context.Background(), "1010", "example.com", records, &BulkOptions{Concurrency: 1, StopOnError: true})
+	if _, ok := err.(*ErrorResponse); !ok {
+		t.Fatalf("Zones.CreateRecords() expected to return an *ErrorResponse, got %v", err)
+	}
+
+	if want, got := int32(1), atomic.LoadInt32(&requests); want != got {
+		t.Errorf("Zones.CreateRecords() expected to send %v requests, got %v", want, got)
+	}
+	for _, result := range results[1:] {
+		if want, got := ErrBulkSkipped, result.Err; want != got {
+			t.Errorf("Zones.CreateRecords() skipped result error expected to be `%v`, got `%v`", want, got)
+		}
+	}
+}
+
+func TestZonesService_CreateRecords_StopOnErrorWhileRateLimited(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	failed := make(chan struct{})
+	mux.HandleFunc("/v2/1010/zones/example.com/records", func(w http.ResponseWriter, r *http.Request) {
+		record := ZoneRecord{}
+		json.NewDecoder(r.Body).Decode(&record)
+
+		if record.Name == "invalid" {
+			close(failed)
+			httpResponse := httpResponseFixture(t, "/api/validation-error.http")
+			w.WriteHeader(httpResponse.StatusCode)
+			io.Copy(w, httpResponse.Body)
+			return
+		}
+
+		// The first record is rate limited once the second one is sent.
+		<-failed
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusTooManyRequests)
+		io.WriteString(w, `{"message":"Your account has exceeded the rate limit."}`)
+	})
+
+	records := []ZoneRecord{{Name: "www"}, {Name: "invalid"}}
+
+	results, err := client.Zones.CreateRecords(context.Background(), "1010", "example.com", records, &BulkOptions{Concurrency: 2, StopOnError: true})
+	if _, ok := err.(*ErrorResponse); !ok {
+		t.Fatalf("Zones.CreateRecords() expected to return the *ErrorResponse of the failed record, got %v", err)
+	}
+	if want, got := ErrBulkSkipped, results[0].Err; want != got {
+		t.Errorf("Zones.CreateRecords() rate limited result error expected to be `%v`, got `%v`", want, got)
+	}
+	if want, got := err, results[1].Err; want != got {
+		t.Errorf("Zones.CreateRecords() failed result error expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestZonesService_CreateRecords_Cancelled(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/zones/example.com/records", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Zones.CreateRecords() expected not to send any request")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := client.Zones.CreateRecords(ctx, "1010", "example.com", []ZoneRecord{{Name: "a"}, {Name: "b"}}, nil)
+	if want, got := context.Canceled, err; want != got {
+		t.Fatalf("Zones.CreateRecords() error expected to be `%v`, got `%v`", want, got)
+	}
+	for _, result := range results {
+		if want, got := context.Canceled, result.Err; want != got {
+			t.Errorf("Zones.CreateRecords() result error expected to be `%v`, got `%v`", want, got)
+		}
+	}
+}
+
+func TestZonesService_CreateRecords_RateLimited(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	defer func(pause time.Duration) { rateLimitMinPause = pause }(rateLimitMinPause)
+	rateLimitMinPause = 10 * time.Millisecond
+
+	var requests int32
+	mux.HandleFunc("/v2/1010/zones/example.com/records", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "1452188712")
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"message":"Your account has exceeded the rate limit."}`)
+			return
+		}
+
+		httpResponse := httpResponseFixture(t, "/api/createZoneRecord/created.http")
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	results, err := client.Zones.CreateRecords(context.Background(), "1010", "example.com", []ZoneRecord{{Name: "www"}}, nil)
+	if err != nil {
+		t.Fatalf("Zones.CreateRecords() returned error: %v", err)
+	}
+
+	if want, got := int32(2), atomic.LoadInt32(&requests); want != got {
+		t.Errorf("Zones.CreateRecords() expected to send %v requests, got %v", want, got)
+	}
+	if want, got := int64(1), results[0].Record.ID; want != got {
+		t.Errorf("Zones.CreateRecords() returned ID expected to be `%v`, got `%v`", want, got)
+	}
+}
+
+func TestZonesService_UpdateRecords(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	mux.HandleFunc("/v2/1010/zones/example.com/records/5", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/updateZoneRecord/success.http")
+
+		testMethod(t, r, "PATCH")
+		testHeaders(t, r)
+
+		want := map[string]interface{}{"id": float64(5), "name": "foo", "content": "127.0.0.1"}
+		testRequestJSON(t, r, want)
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	records := []ZoneRecord{{ID: 5, Name: "foo", Content: "127.0.0.1"}}
+
+	results, err := client.Zones.UpdateRecords(context.Background(), "1010", "example.com", records, nil)
+	if err != nil {
+		t.Fatalf("Zones.UpdateRecords() returned error: %v", err)
+	}
+
+	if results[0].Record == nil {
+		t.Errorf("Zones.UpdateRecords() expected to return the updated record")
+	}
+}
+
+func TestZonesService_DeleteRecords(t *testing.T) {
+	setupMockServer()
+	defer teardownMockServer()
+
+	for _, path := range []string{"/v2/1010/zones/example.com/records/1", "/v2/1010/zones/example.com/records/2"} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			httpResponse := httpResponseFixture(t, "/api/deleteZoneRecord/success.http")
+
+			testMethod(t, r, "DELETE")
+			testHeaders(t, r)
+
+			w.WriteHeader(httpResponse.StatusCode)
+			io.Copy(w, httpResponse.Body)
+		})
+	}
+
+	mux.HandleFunc("/v2/1010/zones/example.com/records/3", func(w http.ResponseWriter, r *http.Request) {
+		httpResponse := httpResponseFixture(t, "/api/notfound-record.http")
+
+		w.WriteHeader(httpResponse.StatusCode)
+		io.Copy(w, httpResponse.Body)
+	})
+
+	results, err := client.Zones.DeleteRecords(context.Background(), "1010", "example.com", []int64{1, 2, 3}, nil)
+	if _, ok := err.(*ErrorResponse); !ok {
+		t.Fatalf("Zones.DeleteRecords() expected to return an *ErrorResponse, got %v", err)
+	}
+
+	if results[0].Err != nil || results[1].Err != nil {
+		t.Errorf("Zones.DeleteRecords() returned errors: %v, %v", results[0].Err, results[1].Err)
+	}
+	if err != results[2].Err {
+		t.Errorf("Zones.DeleteRecords() expected to return the error of the missing record, got %v", results[2].Err)
+	}
+}